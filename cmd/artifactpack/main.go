@@ -0,0 +1,64 @@
+// artifactpack compresses the run's artifacts directory into a single
+// tarball and optionally copies it to an upload destination, enforcing a
+// keep-last-N retention policy there. CI jobs run it as their final step so
+// full HTTP traces and logs don't accumulate unbounded per job.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+)
+
+func main() {
+	uploadDir := flag.String("upload-dir", "", "destination directory for the archive; retention is applied there (optional)")
+	keep := flag.Int("keep", artifacts.DefaultRetainedRuns, "number of run archives to retain in the upload destination")
+	flag.Parse()
+
+	archivePath, err := artifacts.Compress()
+	if err != nil {
+		log.Fatalf("compressing artifacts: %v", err)
+	}
+	log.Printf("wrote %s", archivePath)
+
+	if *uploadDir == "" {
+		return
+	}
+
+	if err := os.MkdirAll(*uploadDir, 0755); err != nil {
+		log.Fatalf("creating upload directory: %v", err)
+	}
+
+	destPath := filepath.Join(*uploadDir, filepath.Base(archivePath))
+	if err := copyFile(archivePath, destPath); err != nil {
+		log.Fatalf("copying archive to upload destination: %v", err)
+	}
+	log.Printf("uploaded %s", destPath)
+
+	if err := artifacts.ApplyRetention(*uploadDir, *keep); err != nil {
+		log.Fatalf("applying retention policy: %v", err)
+	}
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
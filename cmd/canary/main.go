@@ -0,0 +1,168 @@
+// canary loops the full provision→verify→cleanup cycle on a schedule,
+// turning this repo into an always-on provisioning canary. Cycle metrics
+// are recorded like any other run, and an alert webhook fires after a
+// configurable number of consecutive failures.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/templates"
+)
+
+func main() {
+	interval := flag.Duration("interval", time.Hour, "how often to start a canary cycle")
+	templatePath := flag.String("template", "templates/digitalocean.yaml", "provision template to use")
+	alertThreshold := flag.Int("alert-after", 2, "consecutive failures before alerting")
+	webhookURL := flag.String("alert-webhook", "", "webhook URL to POST alerts to (optional)")
+	flag.Parse()
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Fatalf("creating cloud client: %v", err)
+	}
+
+	consecutiveFailures := 0
+	for {
+		start := time.Now()
+		err := runCycle(client, *templatePath)
+		if err != nil {
+			consecutiveFailures++
+			log.Printf("canary cycle failed (%d consecutive): %v", consecutiveFailures, err)
+
+			if consecutiveFailures == *alertThreshold && *webhookURL != "" {
+				alert(*webhookURL, fmt.Sprintf(
+					"e2e canary: %d consecutive provisioning failures; latest: %v",
+					consecutiveFailures, err))
+			}
+		} else {
+			consecutiveFailures = 0
+			log.Printf("canary cycle succeeded in %s", time.Since(start).Round(time.Second))
+		}
+
+		if err := metrics.Flush(); err != nil {
+			log.Printf("warning: flushing cycle metrics: %v", err)
+		}
+
+		// Start cycles on the interval rather than sleeping the full
+		// interval between cycles, unless a cycle overran it.
+		elapsed := time.Since(start)
+		if elapsed < *interval {
+			time.Sleep(*interval - elapsed)
+		}
+	}
+}
+
+// runCycle provisions a cluster, verifies it, and tears it down.
+func runCycle(client *cloud.Client, templatePath string) error {
+	rendered, err := templates.Render(templatePath, templates.DefaultVars(constants.DefaultKubernetesVersion))
+	if err != nil {
+		return err
+	}
+
+	template, err := client.CreateTemplate(rendered.Request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.DeleteTemplate(template.ID); err != nil {
+			log.Printf("warning: deleting canary template %s: %v", template.ID, err)
+		}
+	}()
+
+	cluster, err := client.CreateCluster(&cloud.CreateClusterRequest{
+		TemplateID: template.ID,
+		Name:       fmt.Sprintf("e2e-canary-%d", time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.DeleteCluster(cluster.ID); err != nil {
+			log.Printf("warning: deleting canary cluster %s: %v", cluster.ID, err)
+			return
+		}
+		if err := client.WaitForClusterDeleted(cluster.ID, constants.DeleteTimeout); err != nil {
+			log.Printf("warning: %v", err)
+		}
+	}()
+
+	provisionStart := time.Now()
+	if err := client.WaitForClusterRunning(cluster.ID, constants.ProvisionTimeout); err != nil {
+		return err
+	}
+	metrics.SetRunInfo(rendered.Request.ProviderName, cluster.KubernetesVersion)
+	metrics.Record("provision_seconds", time.Since(provisionStart))
+
+	return verifyCluster(client, cluster.ID)
+}
+
+// verifyCluster runs the same read-only checks as the status suite.
+func verifyCluster(client *cloud.Client, clusterID string) error {
+	cluster, err := client.GetCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Status.Type != cloud.ClusterStatusRunning {
+		return errors.Errorf("cluster %s is %s, expected running", clusterID, cluster.Status.Type)
+	}
+
+	nodePools, err := client.ListNodePools(clusterID)
+	if err != nil {
+		return err
+	}
+	if len(nodePools) == 0 {
+		return errors.Errorf("cluster %s has no node pools", clusterID)
+	}
+	for _, np := range nodePools {
+		if np.Status.Type != cloud.NodePoolStatusRunning {
+			return errors.Errorf("node pool %s is %s, expected running", np.Name, np.Status.Type)
+		}
+	}
+
+	return nil
+}
+
+// alert POSTs a simple text payload to the webhook.
+func alert(webhookURL, message string) {
+	payload, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		log.Printf("warning: marshaling alert payload: %v", err)
+		return
+	}
+
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("warning: posting alert: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("warning: alert webhook returned status %d", resp.StatusCode)
+	}
+}
+
+// usage hint when required env is missing, since the canary is typically
+// run directly rather than through CI wrappers.
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: canary [flags]\n\nRequires %s and %s to be set.\n\nFlags:\n",
+			constants.TokenEnvVar, constants.OrganizationIDEnvVar)
+		flag.PrintDefaults()
+	}
+}
@@ -0,0 +1,97 @@
+// costreport queries AWS Cost Explorer for the actual cost attributed to a
+// run's cluster tag and writes a cost report artifact, so e2e spend can be
+// tracked per provider and per suite. Cost data lags resource usage, so
+// the query optionally waits a configurable delay after the run before
+// asking.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+)
+
+// clusterIDTagKey is the cost allocation tag CKE applies to AWS resources.
+const clusterIDTagKey = "containership-cluster-id"
+
+func main() {
+	clusterID := flag.String("cluster-id", "", "cluster ID whose cost to report (required)")
+	delay := flag.Duration("delay", 0, "how long to wait before querying, since billing data lags usage")
+	days := flag.Int("days", 2, "how many days back to include in the query window")
+	flag.Parse()
+
+	if *clusterID == "" {
+		log.Fatal("--cluster-id is required")
+	}
+
+	if *delay > 0 {
+		log.Printf("waiting %s for billing data to settle", *delay)
+		time.Sleep(*delay)
+	}
+
+	sess, err := session.NewSession()
+	if err != nil {
+		log.Fatalf("creating AWS session: %v", err)
+	}
+
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -*days)
+
+	result, err := costexplorer.New(sess).GetCostAndUsage(&costexplorer.GetCostAndUsageInput{
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Granularity: aws.String(costexplorer.GranularityDaily),
+		Metrics:     []*string{aws.String("UnblendedCost")},
+		Filter: &costexplorer.Expression{
+			Tags: &costexplorer.TagValues{
+				Key:    aws.String(clusterIDTagKey),
+				Values: []*string{clusterID},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("querying Cost Explorer: %v", err)
+	}
+
+	report := costReport{
+		ClusterID: *clusterID,
+		Start:     start.Format("2006-01-02"),
+		End:       end.Format("2006-01-02"),
+	}
+	for _, period := range result.ResultsByTime {
+		cost := period.Total["UnblendedCost"]
+		if cost == nil || cost.Amount == nil {
+			continue
+		}
+
+		var amount float64
+		if _, err := fmt.Sscanf(aws.StringValue(cost.Amount), "%f", &amount); err != nil {
+			log.Fatalf("parsing cost amount %q: %v", aws.StringValue(cost.Amount), err)
+		}
+		report.TotalCostUSD += amount
+	}
+
+	if err := artifacts.WriteJSON(fmt.Sprintf("cost-report-%s.json", *clusterID), report); err != nil {
+		log.Fatalf("writing cost report: %v", err)
+	}
+
+	log.Printf("cluster %s cost $%.2f between %s and %s",
+		report.ClusterID, report.TotalCostUSD, report.Start, report.End)
+}
+
+// costReport is the artifact appended to the run summary.
+type costReport struct {
+	ClusterID    string  `json:"cluster_id"`
+	Start        string  `json:"start"`
+	End          string  `json:"end"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+}
@@ -0,0 +1,323 @@
+// envdiff runs the same read-only verification suites against several
+// Containership environments (typically stage and production) and produces
+// a diff report: cluster versions, specs that behave differently, and
+// per-spec latency deltas. It is the pre-promotion check for backend
+// releases — if stage diverges from production before the promote, the
+// release is what's about to ship that divergence.
+//
+// Each environment needs a cluster to verify against (--cluster-ids) and
+// credentials: the usual CONTAINERSHIP_TOKEN and
+// CONTAINERSHIP_ORGANIZATION_ID are used unless per-environment overrides
+// like CONTAINERSHIP_TOKEN_PRODUCTION are set.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/reporting"
+)
+
+func main() {
+	environments := flag.String("environments", "stage,production",
+		"comma-separated environments to compare; the first is the baseline")
+	suites := flag.String("suites", "status",
+		"comma-separated read-only suites to run against each environment")
+	clusterIDs := flag.String("cluster-ids", "",
+		"per-environment cluster IDs, e.g. stage=<id>,production=<id>")
+	outputDir := flag.String("output-dir", "envdiff-output",
+		"directory for per-environment results and the diff report")
+	stageTimeout := flag.Duration("stage-timeout", 30*time.Minute, "go test timeout applied to each suite run")
+	failOnDiff := flag.Bool("fail-on-diff", false, "exit non-zero when the environments behave differently")
+	flag.Parse()
+
+	envNames := splitList(*environments)
+	if len(envNames) < 2 {
+		log.Fatalf("need at least two environments to compare, got %q", *environments)
+	}
+	clusters, err := parseClusterIDs(*clusterIDs, envNames)
+	if err != nil {
+		log.Fatalf("parsing --cluster-ids: %v", err)
+	}
+
+	report := Report{
+		Environments: envNames,
+		Suites:       splitList(*suites),
+		StartedAt:    time.Now().UTC().Format(time.RFC3339),
+		Clusters:     map[string]ClusterInfo{},
+	}
+
+	runFailed := false
+	outcomes := map[string]map[string]reporting.SpecResult{}
+	for _, env := range envNames {
+		report.Clusters[env] = describeCluster(env, clusters[env])
+
+		envDir := filepath.Join(*outputDir, env)
+		for _, suite := range report.Suites {
+			if err := runSuite(env, suite, clusters[env], envDir, *stageTimeout); err != nil {
+				log.Printf("%s: %s suite failed: %v", env, suite, err)
+				runFailed = true
+			}
+		}
+
+		results, err := loadResults(envDir)
+		if err != nil {
+			log.Fatalf("loading %s results: %v", env, err)
+		}
+		outcomes[env] = results
+	}
+
+	diff(&report, envNames, outcomes)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("marshaling report: %v", err)
+	}
+	reportPath := filepath.Join(*outputDir, "envdiff-report.json")
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		log.Fatalf("writing report: %v", err)
+	}
+
+	for env, info := range report.Clusters {
+		log.Printf("%s: cluster %s, Kubernetes %s, provider %s",
+			env, info.ClusterID, orUnknown(info.KubernetesVersion), orUnknown(info.Provider))
+	}
+	for _, difference := range report.Differences {
+		log.Printf("behavior diff: %q is %s", difference.Spec, describeStates(difference.States))
+	}
+	log.Printf("report written to %s (%d behavior differences)", reportPath, len(report.Differences))
+
+	if runFailed {
+		log.Fatalf("one or more suite runs failed; see output above")
+	}
+	if *failOnDiff && len(report.Differences) > 0 {
+		log.Fatalf("environments behave differently")
+	}
+}
+
+// Report is the diff document envdiff writes.
+type Report struct {
+	Environments []string               `json:"environments"`
+	Suites       []string               `json:"suites"`
+	StartedAt    string                 `json:"started_at"`
+	Clusters     map[string]ClusterInfo `json:"clusters"`
+	// Differences are specs whose state differs between environments.
+	Differences []Difference `json:"differences,omitempty"`
+	// Latencies compare per-spec durations for specs that passed
+	// everywhere, baseline environment first.
+	Latencies []Latency `json:"latencies,omitempty"`
+}
+
+// ClusterInfo identifies the cluster verified in one environment.
+type ClusterInfo struct {
+	ClusterID         string `json:"cluster_id"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	Provider          string `json:"provider,omitempty"`
+}
+
+// Difference is one spec with a different outcome per environment.
+type Difference struct {
+	Spec   string            `json:"spec"`
+	States map[string]string `json:"states"`
+}
+
+// Latency is one spec's duration per environment, with the delta of the
+// slowest non-baseline environment against the baseline.
+type Latency struct {
+	Spec         string             `json:"spec"`
+	Seconds      map[string]float64 `json:"seconds"`
+	DeltaSeconds float64            `json:"delta_seconds"`
+}
+
+// runSuite runs one read-only suite against an environment's cluster,
+// writing results into the environment's output directory.
+func runSuite(env, suite, clusterID, outputDir string, timeout time.Duration) error {
+	log.Printf("=== %s: %s suite ===", env, suite)
+
+	cmd := exec.Command("go", "test", "./test/"+suite+"/...",
+		"-count=1", "-timeout", timeout.String(), "-v", "-read-only")
+	cmd.Env = append(os.Environ(),
+		constants.EnvironmentEnvVar+"="+env,
+		constants.ClusterIDEnvVar+"="+clusterID,
+		constants.OutputDirEnvVar+"="+outputDir,
+	)
+	cmd.Env = append(cmd.Env, credentialOverrides(env)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// credentialOverrides maps per-environment credential variables (e.g.
+// CONTAINERSHIP_TOKEN_PRODUCTION) onto the variables the suites read.
+func credentialOverrides(env string) []string {
+	suffix := "_" + strings.ToUpper(env)
+	var overrides []string
+	for _, name := range []string{constants.TokenEnvVar, constants.OrganizationIDEnvVar} {
+		if value := os.Getenv(name + suffix); value != "" {
+			overrides = append(overrides, name+"="+value)
+		}
+	}
+	return overrides
+}
+
+// describeCluster asks the environment's provision API about the cluster,
+// applying the same credential overrides the suites run with. Lookup
+// failures degrade to an ID-only record rather than aborting the
+// comparison.
+func describeCluster(env, clusterID string) ClusterInfo {
+	info := ClusterInfo{ClusterID: clusterID}
+
+	// cloud.New resolves the environment and credentials from the process
+	// environment, so set them the same way the child suites see them.
+	os.Setenv(constants.EnvironmentEnvVar, env)
+	suffix := "_" + strings.ToUpper(env)
+	for _, name := range []string{constants.TokenEnvVar, constants.OrganizationIDEnvVar} {
+		if value := os.Getenv(name + suffix); value != "" {
+			os.Setenv(name, value)
+		}
+	}
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Printf("%s: building cloud client: %v", env, err)
+		return info
+	}
+	cluster, err := client.GetCluster(clusterID)
+	if err != nil {
+		log.Printf("%s: describing cluster %s: %v", env, clusterID, err)
+		return info
+	}
+
+	info.KubernetesVersion = cluster.KubernetesVersion
+	info.Provider = cluster.ProviderName
+	return info
+}
+
+// loadResults reads every per-suite JSON summary in the directory into one
+// spec-to-result map.
+func loadResults(dir string) (map[string]reporting.SpecResult, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "results-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[string]reporting.SpecResult{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var summary reporting.Summary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", path, err)
+		}
+		for _, spec := range summary.Specs {
+			results[summary.Suite+": "+spec.Spec] = spec
+		}
+	}
+	return results, nil
+}
+
+// diff fills the report's differences and latencies by comparing every
+// environment's outcomes against the full spec set.
+func diff(report *Report, envNames []string, outcomes map[string]map[string]reporting.SpecResult) {
+	specs := map[string]bool{}
+	for _, results := range outcomes {
+		for spec := range results {
+			specs[spec] = true
+		}
+	}
+
+	for spec := range specs {
+		states := map[string]string{}
+		seconds := map[string]float64{}
+		allPassed := true
+		differs := false
+		for _, env := range envNames {
+			result, ran := outcomes[env][spec]
+			if !ran {
+				states[env] = "not_run"
+				allPassed = false
+			} else {
+				states[env] = result.State
+				seconds[env] = result.DurationSeconds
+				if result.State != "passed" {
+					allPassed = false
+				}
+			}
+			if states[env] != states[envNames[0]] {
+				differs = true
+			}
+		}
+
+		if differs {
+			report.Differences = append(report.Differences, Difference{Spec: spec, States: states})
+		}
+		if allPassed {
+			baseline := seconds[envNames[0]]
+			delta := 0.0
+			for _, env := range envNames[1:] {
+				if d := seconds[env] - baseline; d > delta {
+					delta = d
+				}
+			}
+			report.Latencies = append(report.Latencies, Latency{
+				Spec:         spec,
+				Seconds:      seconds,
+				DeltaSeconds: delta,
+			})
+		}
+	}
+}
+
+func parseClusterIDs(raw string, envNames []string) (map[string]string, error) {
+	clusters := map[string]string{}
+	for _, pair := range splitList(raw) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("malformed entry %q (want env=cluster-id)", pair)
+		}
+		clusters[parts[0]] = parts[1]
+	}
+	for _, env := range envNames {
+		if clusters[env] == "" {
+			return nil, fmt.Errorf("no cluster ID for environment %s", env)
+		}
+	}
+	return clusters, nil
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func describeStates(states map[string]string) string {
+	var parts []string
+	for env, state := range states {
+		parts = append(parts, fmt.Sprintf("%s in %s", state, env))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+// kubectlmatrix smoke-tests the cluster with multiple kubectl versions
+// (typically n-1, n, n+1 relative to the cluster) through the proxy
+// kubeconfig. Customers run a spread of client versions; this catches proxy
+// or API incompatibilities with clients we don't otherwise exercise. CI
+// provides the binaries and passes their paths in.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/k8s"
+)
+
+// probeManifest is the object each kubectl version applies and deletes, so
+// the matrix covers writes as well as reads.
+const probeManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kubectl-matrix-probe
+  namespace: default
+data:
+  probe: "true"
+`
+
+// step is one kubectl invocation in the smoke sequence.
+type step struct {
+	name  string
+	args  []string
+	stdin string
+}
+
+var steps = []step{
+	{name: "version", args: []string{"version"}},
+	{name: "get-nodes", args: []string{"get", "nodes"}},
+	{name: "get-pods", args: []string{"get", "pods", "--all-namespaces"}},
+	{name: "apply", args: []string{"apply", "-f", "-"}, stdin: probeManifest},
+	{name: "get-probe", args: []string{"get", "configmap", "kubectl-matrix-probe", "-n", "default"}},
+	{name: "delete-probe", args: []string{"delete", "configmap", "kubectl-matrix-probe", "-n", "default"}},
+}
+
+// binaryResult records how one kubectl binary fared.
+type binaryResult struct {
+	Binary string            `json:"binary"`
+	Steps  map[string]string `json:"steps"`
+	Failed bool              `json:"failed"`
+}
+
+func main() {
+	binaries := flag.String("kubectl", "", "comma-separated paths to the kubectl binaries to exercise")
+	clusterID := flag.String("cluster-id", os.Getenv(constants.ClusterIDEnvVar), "cluster to run against")
+	flag.Parse()
+
+	if *binaries == "" || *clusterID == "" {
+		log.Fatalf("both -kubectl and -cluster-id (or %s) are required", constants.ClusterIDEnvVar)
+	}
+
+	kubeconfig, err := k8s.Kubeconfig(*clusterID)
+	if err != nil {
+		log.Fatalf("rendering kubeconfig: %v", err)
+	}
+
+	kubeconfigPath, err := writeTempKubeconfig(kubeconfig)
+	if err != nil {
+		log.Fatalf("writing kubeconfig: %v", err)
+	}
+	defer os.Remove(kubeconfigPath)
+
+	var results []binaryResult
+	anyFailed := false
+	for _, binary := range strings.Split(*binaries, ",") {
+		binary = strings.TrimSpace(binary)
+		if binary == "" {
+			continue
+		}
+
+		result := runBinary(binary, kubeconfigPath)
+		if result.Failed {
+			anyFailed = true
+		}
+		results = append(results, result)
+	}
+
+	if err := artifacts.WriteJSON("kubectl-matrix.json", results); err != nil {
+		log.Printf("warning: writing kubectl matrix artifact: %v", err)
+	}
+	if anyFailed {
+		log.Fatal("kubectl matrix failed; see kubectl-matrix.json")
+	}
+	log.Printf("kubectl matrix passed for %d binaries", len(results))
+}
+
+// runBinary runs the full smoke sequence with one kubectl binary.
+func runBinary(binary, kubeconfigPath string) binaryResult {
+	result := binaryResult{
+		Binary: binary,
+		Steps:  map[string]string{},
+	}
+
+	for _, s := range steps {
+		log.Printf("%s: %s", filepath.Base(binary), s.name)
+
+		cmd := exec.Command(binary, s.args...)
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfigPath)
+		if s.stdin != "" {
+			cmd.Stdin = strings.NewReader(s.stdin)
+		}
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		if err := cmd.Run(); err != nil {
+			result.Steps[s.name] = fmt.Sprintf("%v: %s", err, output.String())
+			result.Failed = true
+			log.Printf("%s: %s failed: %v", filepath.Base(binary), s.name, err)
+			continue
+		}
+		result.Steps[s.name] = "ok"
+	}
+
+	return result
+}
+
+func writeTempKubeconfig(data []byte) (string, error) {
+	f, err := os.CreateTemp("", "e2e-kubeconfig-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,172 @@
+// resetorg wipes all e2e-created resources from the test organization —
+// clusters, templates, SSH keys, and webhooks — and verifies the org is
+// back to the seeded baseline (standing fixtures from the seed command are
+// left in place). It is the periodic deep clean for the shared test org;
+// the sweeper's age-based collection handles routine leaks. Because it
+// deletes everything regardless of age, it refuses to run unless the
+// target organization ID is repeated on the command line.
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/lock"
+	"github.com/containership/e2e-test/pkg/sweep"
+)
+
+func main() {
+	confirmOrg := flag.String("confirm-org", "",
+		"ID of the organization to reset; must match the configured organization exactly")
+	dryRun := flag.Bool("dry-run", false, "print what would be removed without deleting anything")
+	deleteTimeout := flag.Duration("delete-timeout", constants.DeleteTimeout, "how long to wait for each cluster deletion")
+	flag.Parse()
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Fatalf("building cloud client: %v", err)
+	}
+
+	// The confirmation guard: resetting the wrong org deletes every e2e
+	// resource in it, so the target must be named explicitly.
+	if !*dryRun && *confirmOrg != client.OrganizationID() {
+		log.Fatalf("refusing to reset organization %s: pass --confirm-org %s to proceed",
+			client.OrganizationID(), client.OrganizationID())
+	}
+
+	if orgLock := lock.FromEnv(); orgLock != nil && !*dryRun {
+		if err := orgLock.Acquire(constants.LockAcquireTimeout); err != nil {
+			log.Fatalf("acquiring org lock: %v", err)
+		}
+		defer func() {
+			if err := orgLock.Release(); err != nil {
+				log.Printf("releasing org lock: %v", err)
+			}
+		}()
+	}
+
+	// Age zero makes every e2e cluster and template a candidate; the seeded
+	// baseline templates are excluded by the sweep package itself.
+	candidates, err := sweep.FindOrphans(client, 0)
+	if err != nil {
+		log.Fatalf("finding e2e resources: %v", err)
+	}
+	for _, candidate := range candidates {
+		verb := "removing"
+		if *dryRun {
+			verb = "would remove"
+		}
+		log.Printf("%s %s %s (%s)", verb, candidate.Kind, candidate.ID, candidate.Name)
+	}
+	if !*dryRun {
+		if err := sweep.Remove(client, candidates, *deleteTimeout); err != nil {
+			log.Fatalf("removing e2e resources: %v", err)
+		}
+	}
+
+	if err := resetSSHKeys(client, *dryRun); err != nil {
+		log.Fatalf("removing SSH keys: %v", err)
+	}
+	if err := resetWebhooks(client, *dryRun); err != nil {
+		log.Fatalf("removing webhooks: %v", err)
+	}
+
+	if *dryRun {
+		return
+	}
+
+	if err := verifyBaseline(client); err != nil {
+		log.Fatalf("verifying baseline: %v", err)
+	}
+	log.Printf("organization %s is back to the seeded baseline", client.OrganizationID())
+}
+
+// resetSSHKeys deletes every e2e-prefixed SSH key except the standing seed
+// key.
+func resetSSHKeys(client *cloud.Client, dryRun bool) error {
+	keys, err := client.ListSSHKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key.Name, cloud.SSHKeyNamePrefix) || key.Name == sweep.SeedSSHKeyName {
+			continue
+		}
+		if dryRun {
+			log.Printf("would remove SSH key %s (%s)", key.ID, key.Name)
+			continue
+		}
+		if err := client.DeleteSSHKey(key.ID); err != nil {
+			return err
+		}
+		log.Printf("removed SSH key %s (%s)", key.ID, key.Name)
+	}
+
+	return nil
+}
+
+// resetWebhooks deletes every webhook in the org. Webhook receivers are
+// ephemeral per-run endpoints, so any subscription still registered in the
+// test org is a leftover.
+func resetWebhooks(client *cloud.Client, dryRun bool) error {
+	webhooks, err := client.ListWebhooks()
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if dryRun {
+			log.Printf("would remove webhook %s (%s)", webhook.ID, webhook.URL)
+			continue
+		}
+		if err := client.DeleteWebhook(webhook.ID); err != nil {
+			return err
+		}
+		log.Printf("removed webhook %s (%s)", webhook.ID, webhook.URL)
+	}
+
+	return nil
+}
+
+// verifyBaseline re-lists everything and errors if any e2e resource beyond
+// the seeded fixtures survived the reset.
+func verifyBaseline(client *cloud.Client) error {
+	var leftovers []string
+
+	candidates, err := sweep.FindOrphans(client, 0)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		leftovers = append(leftovers, candidate.Kind+" "+candidate.ID)
+	}
+
+	keys, err := client.ListSSHKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if strings.HasPrefix(key.Name, cloud.SSHKeyNamePrefix) && key.Name != sweep.SeedSSHKeyName {
+			leftovers = append(leftovers, "ssh-key "+key.ID)
+		}
+	}
+
+	webhooks, err := client.ListWebhooks()
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		leftovers = append(leftovers, "webhook "+webhook.ID)
+	}
+
+	if len(leftovers) > 0 {
+		return errors.Errorf("resources survived the reset: %s", strings.Join(leftovers, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// seed makes a fresh test organization e2e-ready by pre-creating the
+// standing fixtures the suites assume: a baseline template per provider
+// template file and an optional standing SSH key, and by reporting which
+// providers have credentials linked. It is idempotent — fixtures that
+// already exist are left alone — so it can run on every pipeline setup
+// without accumulating duplicates. Provider links themselves require
+// provider-side secrets and can only be verified, not created, here.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/providers"
+	"github.com/containership/e2e-test/pkg/sweep"
+	"github.com/containership/e2e-test/pkg/templates"
+)
+
+// seedDescriptionPrefix marks templates created by this command; the
+// template file's base name follows it, giving the idempotency key. The
+// sweeper recognizes the prefix and leaves standing fixtures alone.
+const seedDescriptionPrefix = sweep.SeedDescriptionPrefix
+
+// seedSSHKeyName names the standing SSH key; the sweeper and reset-org
+// both recognize it and leave it in place.
+const seedSSHKeyName = sweep.SeedSSHKeyName
+
+func main() {
+	templatesDir := flag.String("templates-dir", "templates",
+		"directory of provider template files to seed as the baseline template set")
+	kubernetesVersion := flag.String("kubernetes-version", constants.DefaultKubernetesVersion,
+		"Kubernetes version the baseline templates are rendered with")
+	sshKeyFile := flag.String("ssh-key-file", "",
+		"SSH public key file to upload as the standing key (skipped when empty)")
+	dryRun := flag.Bool("dry-run", false, "print what would be created without creating anything")
+	flag.Parse()
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Fatalf("building cloud client: %v", err)
+	}
+
+	reportProviderLinks(client)
+
+	if err := seedSSHKey(client, *sshKeyFile, *dryRun); err != nil {
+		log.Fatalf("seeding SSH key: %v", err)
+	}
+
+	if err := seedTemplates(client, *templatesDir, *kubernetesVersion, *dryRun); err != nil {
+		log.Fatalf("seeding templates: %v", err)
+	}
+}
+
+// reportProviderLinks reports which registered providers have credentials
+// linked to the org. Missing links are a warning, not an error: linking
+// requires provider secrets this command does not have.
+func reportProviderLinks(client *cloud.Client) {
+	links, err := client.ListProviderLinks()
+	if err != nil {
+		log.Printf("warning: listing provider links: %v", err)
+		return
+	}
+
+	linked := map[string]bool{}
+	for _, link := range links {
+		linked[link.Provider] = true
+	}
+
+	for _, name := range providers.Names() {
+		if linked[name] {
+			log.Printf("provider %s: credentials linked", name)
+		} else {
+			log.Printf("warning: provider %s has no credentials linked; suites for it will fail until it is linked in the console", name)
+		}
+	}
+}
+
+// seedSSHKey uploads the standing SSH key unless one with the seed name
+// already exists.
+func seedSSHKey(client *cloud.Client, keyFile string, dryRun bool) error {
+	if keyFile == "" {
+		log.Printf("no --ssh-key-file given; skipping standing SSH key")
+		return nil
+	}
+
+	keys, err := client.ListSSHKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if key.Name == seedSSHKeyName {
+			log.Printf("SSH key %s already exists (%s); leaving it alone", seedSSHKeyName, key.ID)
+			return nil
+		}
+	}
+
+	publicKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("reading SSH public key %s: %v", keyFile, err)
+	}
+
+	if dryRun {
+		log.Printf("would upload SSH key %s from %s", seedSSHKeyName, keyFile)
+		return nil
+	}
+
+	key, err := client.CreateSSHKey(&cloud.CreateSSHKeyRequest{
+		Name:      seedSSHKeyName,
+		PublicKey: strings.TrimSpace(string(publicKey)),
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("uploaded SSH key %s (%s)", seedSSHKeyName, key.ID)
+
+	return nil
+}
+
+// seedTemplates renders every template file in the directory and creates
+// the ones the org doesn't already have, keyed by the seed description.
+func seedTemplates(client *cloud.Client, dir, kubernetesVersion string, dryRun bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading templates directory %s: %v", dir, err)
+	}
+
+	existing, err := client.ListTemplates()
+	if err != nil {
+		return err
+	}
+	seeded := map[string]bool{}
+	for _, template := range existing {
+		if strings.HasPrefix(template.Description, seedDescriptionPrefix) {
+			seeded[strings.TrimPrefix(template.Description, seedDescriptionPrefix)] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		if seeded[entry.Name()] {
+			log.Printf("template for %s already seeded; leaving it alone", entry.Name())
+			continue
+		}
+
+		rendered, err := templates.Render(filepath.Join(dir, entry.Name()), templates.DefaultVars(kubernetesVersion))
+		if err != nil {
+			return err
+		}
+		for _, warning := range rendered.Warnings {
+			log.Printf("warning: %s", warning)
+		}
+
+		request := rendered.Request
+		request.Description = seedDescriptionPrefix + entry.Name()
+		if err := providers.DefaultAndValidate(request); err != nil {
+			return err
+		}
+
+		if dryRun {
+			log.Printf("would create template for %s (provider %s)", entry.Name(), request.ProviderName)
+			continue
+		}
+
+		template, err := client.CreateTemplate(request)
+		if err != nil {
+			return err
+		}
+		log.Printf("created template %s for %s (provider %s)", template.ID, entry.Name(), request.ProviderName)
+	}
+
+	return nil
+}
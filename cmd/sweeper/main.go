@@ -0,0 +1,70 @@
+// sweeper garbage-collects orphaned e2e clusters and templates from the
+// test organization. CI runs it on a schedule; failed pipelines leak
+// resources and this is the backstop. It takes the shared org lock (when
+// configured) so it never deletes a cluster out from under a live run.
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/lock"
+	"github.com/containership/e2e-test/pkg/sweep"
+)
+
+func main() {
+	maxAge := flag.Duration("max-age", 6*time.Hour, "minimum age before an e2e resource is considered orphaned")
+	dryRun := flag.Bool("dry-run", false, "print what would be removed without deleting anything")
+	deleteTimeout := flag.Duration("delete-timeout", constants.DeleteTimeout, "how long to wait for each cluster deletion")
+	flag.Parse()
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Fatalf("building cloud client: %v", err)
+	}
+
+	if orgLock := lock.FromEnv(); orgLock != nil && !*dryRun {
+		if err := orgLock.Acquire(constants.LockAcquireTimeout); err != nil {
+			log.Fatalf("acquiring org lock: %v", err)
+		}
+		defer func() {
+			if err := orgLock.Release(); err != nil {
+				log.Printf("releasing org lock: %v", err)
+			}
+		}()
+	}
+
+	candidates, err := sweep.FindOrphans(client, *maxAge)
+	if err != nil {
+		log.Fatalf("finding orphans: %v", err)
+	}
+	if len(candidates) == 0 {
+		log.Printf("no orphaned e2e resources older than %s", *maxAge)
+		return
+	}
+
+	for _, candidate := range candidates {
+		verb := "removing"
+		if *dryRun {
+			verb = "would remove"
+		}
+		detail := ""
+		if candidate.Owner != "" {
+			detail = ", " + candidate.Owner
+		}
+		log.Printf("%s %s %s (%s, age %s%s)",
+			verb, candidate.Kind, candidate.ID, candidate.Name,
+			candidate.Age.Round(time.Minute), detail)
+	}
+	if *dryRun {
+		return
+	}
+
+	if err := sweep.Remove(client, candidates, *deleteTimeout); err != nil {
+		log.Fatalf("removing orphans: %v", err)
+	}
+	log.Printf("removed %d orphaned resources", len(candidates))
+}
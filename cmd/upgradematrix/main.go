@@ -0,0 +1,141 @@
+// upgradematrix generates the valid upgrade path from the supported
+// Kubernetes versions (n-2 → n-1 → n) and executes it: provision at the
+// oldest version, upgrade step by step, verify after each hop, then tear
+// down. Nightly jobs run this instead of hand-maintaining upgrade job
+// definitions that go stale every release.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/templates"
+	"github.com/containership/e2e-test/pkg/versions"
+)
+
+func main() {
+	templatePath := flag.String("template", "templates/digitalocean.yaml", "provision template to use")
+	flag.Parse()
+
+	client, err := cloud.New()
+	if err != nil {
+		log.Fatalf("creating cloud client: %v", err)
+	}
+
+	supported, err := client.ListSupportedKubernetesVersions()
+	if err != nil {
+		log.Fatalf("listing supported versions: %v", err)
+	}
+
+	path, err := versions.UpgradePath(supported)
+	if err != nil {
+		log.Fatalf("generating upgrade path: %v", err)
+	}
+	if len(path) < 2 {
+		log.Fatalf("only one supported minor stream (%v); nothing to upgrade", path)
+	}
+	log.Printf("executing upgrade path: %s", strings.Join(path, " -> "))
+
+	result := matrixResult{Path: path}
+	if err := runPath(client, *templatePath, path, &result); err != nil {
+		result.Error = err.Error()
+	}
+
+	if artifactErr := artifacts.WriteJSON("upgrade-matrix.json", result); artifactErr != nil {
+		log.Printf("warning: writing upgrade matrix artifact: %v", artifactErr)
+	}
+	if result.Error != "" {
+		log.Fatalf("upgrade path failed: %s", result.Error)
+	}
+	log.Print("upgrade path completed successfully")
+}
+
+// matrixResult is the artifact summarizing an upgrade path execution.
+type matrixResult struct {
+	Path  []string      `json:"path"`
+	Hops  []upgradeStep `json:"hops,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+type upgradeStep struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Seconds float64 `json:"seconds"`
+}
+
+// runPath provisions at path[0] and upgrades through the rest.
+func runPath(client *cloud.Client, templatePath string, path []string, result *matrixResult) error {
+	rendered, err := templates.Render(templatePath, templates.DefaultVars(path[0]))
+	if err != nil {
+		return err
+	}
+
+	template, err := client.CreateTemplate(rendered.Request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.DeleteTemplate(template.ID); err != nil {
+			log.Printf("warning: deleting template %s: %v", template.ID, err)
+		}
+	}()
+
+	cluster, err := client.CreateCluster(&cloud.CreateClusterRequest{
+		TemplateID: template.ID,
+		Name:       fmt.Sprintf("e2e-upgrade-%d", time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.DeleteCluster(cluster.ID); err != nil {
+			log.Printf("warning: deleting cluster %s: %v", cluster.ID, err)
+			return
+		}
+		if err := client.WaitForClusterDeleted(cluster.ID, constants.DeleteTimeout); err != nil {
+			log.Printf("warning: %v", err)
+		}
+	}()
+
+	if err := client.WaitForClusterRunning(cluster.ID, constants.ProvisionTimeout); err != nil {
+		return err
+	}
+
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+		log.Printf("upgrading cluster %s: %s -> %s", cluster.ID, from, to)
+
+		start := time.Now()
+		if err := client.UpgradeCluster(cluster.ID, to); err != nil {
+			return err
+		}
+		if err := client.WaitForClusterRunning(cluster.ID, constants.ProvisionTimeout); err != nil {
+			return err
+		}
+
+		upgraded, err := client.GetCluster(cluster.ID)
+		if err != nil {
+			return err
+		}
+		if upgraded.KubernetesVersion != to {
+			return fmt.Errorf("cluster %s reports version %s after upgrade to %s",
+				cluster.ID, upgraded.KubernetesVersion, to)
+		}
+
+		result.Hops = append(result.Hops, upgradeStep{
+			From:    from,
+			To:      to,
+			Seconds: time.Since(start).Seconds(),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// DefaultRetainedRuns is how many archived runs the retention policy keeps
+// per job when no explicit count is given.
+const DefaultRetainedRuns = 10
+
+// Compress tars and gzips the artifacts root into a single archive next to
+// it, named after the current run, and returns the archive path. Full HTTP
+// traces plus logs per run add up; uploading one compressed file keeps the
+// destination manageable.
+func Compress() (string, error) {
+	root := os.Getenv(constants.ArtifactsEnvVar)
+	if root == "" {
+		root = defaultDir
+	}
+
+	archivePath := filepath.Join(filepath.Dir(root), fmt.Sprintf("%s.tar.gz", runSegment()))
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "creating archive %s", archivePath)
+	}
+	defer out.Close()
+
+	gzipWriter := gzip.NewWriter(out)
+	defer gzipWriter.Close()
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || path == archivePath {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relPath
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "archiving %s", root)
+	}
+
+	return archivePath, nil
+}
+
+// ApplyRetention enforces a keep-last-N policy on run archives in the given
+// upload destination directory, deleting the oldest beyond keep. Only files
+// matching the run archive naming convention are touched.
+func ApplyRetention(uploadDir string, keep int) error {
+	if keep <= 0 {
+		keep = DefaultRetainedRuns
+	}
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		return errors.Wrapf(err, "reading upload directory %s", uploadDir)
+	}
+
+	var archives []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "run-") || !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Wrapf(err, "statting archive %s", entry.Name())
+		}
+		archives = append(archives, info)
+	}
+
+	if len(archives) <= keep {
+		return nil
+	}
+
+	// Newest first; everything past the keep window goes.
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ModTime().After(archives[j].ModTime())
+	})
+
+	for _, stale := range archives[keep:] {
+		path := filepath.Join(uploadDir, stale.Name())
+		if err := os.Remove(path); err != nil {
+			return errors.Wrapf(err, "removing stale archive %s", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+// Package artifacts handles writing test artifacts (logs, dumps, reports)
+// to the artifacts directory for collection by CI.
+//
+// Artifacts are laid out per run, per suite, per parallel ginkgo node, and
+// per spec:
+//
+//	<root>/<run>/<suite>/<node>/<spec>/<file>
+//
+// so concurrent suites, parallel nodes, and background collectors never
+// clobber each other's diagnostics. Scope segments are only present once
+// set: a write from suite setup lands at the suite level, a write from a
+// background collector with no spec in flight likewise, and the cmd/ tools
+// keep writing at the root.
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo/config"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// defaultDir is used when the artifacts environment variable is unset, e.g.
+// for local runs.
+const defaultDir = "artifacts"
+
+// Scope state shared between specs and background collectors; guarded so
+// collectors sampling from goroutines see a consistent scope.
+var (
+	mu        sync.Mutex
+	suiteName string
+	specName  string
+)
+
+// SetSuite records the suite currently running, scoping subsequent writes
+// under a per-suite directory. Call it once from the suite entry point.
+func SetSuite(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	suiteName = slugify(name)
+}
+
+// SetSpec records the spec currently running, scoping subsequent writes
+// under a per-spec directory. Pair with ClearSpec from an AfterEach so
+// teardown writes land at the suite level again.
+func SetSpec(fullText string) {
+	mu.Lock()
+	defer mu.Unlock()
+	specName = slugify(fullText)
+}
+
+// ClearSpec clears the per-spec scope.
+func ClearSpec() {
+	mu.Lock()
+	defer mu.Unlock()
+	specName = ""
+}
+
+// slugify turns free-form names (suite titles, spec text) into filesystem-
+// safe path segments.
+func slugify(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name)
+
+	slug = strings.Trim(slug, "-")
+	const maxLen = 80
+	if len(slug) > maxLen {
+		slug = slug[:maxLen]
+	}
+	return slug
+}
+
+// runSegment identifies this run. CI builds are keyed by build number so
+// reruns don't mix; local runs share one directory.
+func runSegment() string {
+	if build := os.Getenv("BUILD_NUMBER"); build != "" {
+		return "run-" + build
+	}
+	return "run-local"
+}
+
+// nodeSegment identifies the parallel ginkgo node, so parallel nodes of
+// one suite write side by side instead of over each other.
+func nodeSegment() string {
+	if config.GinkgoConfig.ParallelTotal > 1 {
+		return fmt.Sprintf("node-%d", config.GinkgoConfig.ParallelNode)
+	}
+	return ""
+}
+
+// Dir returns the directory artifacts should be written to for the current
+// scope, creating it if necessary.
+func Dir() (string, error) {
+	root := os.Getenv(constants.ArtifactsEnvVar)
+	if root == "" {
+		root = defaultDir
+	}
+
+	mu.Lock()
+	segments := []string{root}
+	if suiteName != "" {
+		segments = append(segments, runSegment(), suiteName)
+		if node := nodeSegment(); node != "" {
+			segments = append(segments, node)
+		}
+		if specName != "" {
+			segments = append(segments, specName)
+		}
+	}
+	mu.Unlock()
+
+	dir := filepath.Join(segments...)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "creating artifacts directory %s", dir)
+	}
+
+	return dir, nil
+}
+
+// RunDir returns the run-scoped artifacts directory (no spec segment),
+// creating it if necessary. Writers that produce one file for the whole
+// run (e.g. the run log) use this instead of Dir, which follows the spec
+// in flight.
+func RunDir() (string, error) {
+	root := os.Getenv(constants.ArtifactsEnvVar)
+	if root == "" {
+		root = defaultDir
+	}
+
+	mu.Lock()
+	segments := []string{root}
+	if suiteName != "" {
+		segments = append(segments, runSegment(), suiteName)
+		if node := nodeSegment(); node != "" {
+			segments = append(segments, node)
+		}
+	}
+	mu.Unlock()
+
+	dir := filepath.Join(segments...)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "creating artifacts directory %s", dir)
+	}
+
+	return dir, nil
+}
+
+// WriteJSON marshals v with indentation and writes it to the named file in
+// the artifacts directory.
+func WriteJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "marshaling artifact %s", name)
+	}
+
+	return WriteFile(name, data)
+}
+
+// WriteFile writes data to the named file in the artifacts directory.
+func WriteFile(name string, data []byte) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+	return errors.Wrapf(os.WriteFile(path, data, 0644), "writing artifact %s", path)
+}
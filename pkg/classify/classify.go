@@ -0,0 +1,94 @@
+// Package classify assigns structured categories to spec failures so triage
+// dashboards can see what actually kills nightly runs (provider capacity vs
+// our own assertions) without reading every log. Failures are classified
+// from their message text at fail time, recorded to an artifact, and
+// counted into the run metrics.
+package classify
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/metrics"
+)
+
+// Category is a structured failure classification code.
+type Category string
+
+// The failure categories, roughly ordered from "not our fault" to "our
+// assertion fired".
+const (
+	// CategoryProviderCapacity is a cloud provider capacity or quota issue.
+	CategoryProviderCapacity Category = "provider-capacity"
+	// CategoryCloudAPIError is an unexpected response from the
+	// Containership APIs.
+	CategoryCloudAPIError Category = "cloud-api-error"
+	// CategoryKubeUnreachable is a failure to reach the cluster's
+	// Kubernetes API.
+	CategoryKubeUnreachable Category = "kube-unreachable"
+	// CategoryTimeout is a wait that exceeded its deadline.
+	CategoryTimeout Category = "timeout"
+	// CategoryAssertion is everything else: a test assertion about actual
+	// behavior failed.
+	CategoryAssertion Category = "assertion"
+)
+
+// markers maps message substrings to categories. Earlier entries win, so
+// the more specific external causes are checked before the generic ones.
+var markers = []struct {
+	substrings []string
+	category   Category
+}{
+	{[]string{"capacity", "quota", "rate limit", "too many requests"}, CategoryProviderCapacity},
+	{[]string{"k8sapi", "kubernetes api", "kubeconfig", "connection refused", "no such host"}, CategoryKubeUnreachable},
+	{[]string{"unexpected status 5", "unexpected status 429", "containership.io: unexpected status"}, CategoryCloudAPIError},
+	{[]string{"timed out", "timeout", "deadline exceeded"}, CategoryTimeout},
+}
+
+// Classify assigns a category to a failure message.
+func Classify(message string) Category {
+	lower := strings.ToLower(message)
+	for _, marker := range markers {
+		for _, substring := range marker.substrings {
+			if strings.Contains(lower, substring) {
+				return marker.category
+			}
+		}
+	}
+	return CategoryAssertion
+}
+
+// Failure is one classified spec failure.
+type Failure struct {
+	Spec     string   `json:"spec"`
+	Category Category `json:"category"`
+	Message  string   `json:"message"`
+}
+
+var (
+	mu       sync.Mutex
+	failures []Failure
+)
+
+// Record classifies and records a spec failure, updating the failure
+// classification artifact and the run metrics counters.
+func Record(specText, message string) Category {
+	category := Classify(message)
+
+	mu.Lock()
+	failures = append(failures, Failure{
+		Spec:     specText,
+		Category: category,
+		Message:  message,
+	})
+	snapshot := append([]Failure(nil), failures...)
+	mu.Unlock()
+
+	// Rewrite on every failure so the report survives an aborted run.
+	//nolint:errcheck - reporting must not mask the failure itself
+	artifacts.WriteJSON("failure-classification.json", snapshot)
+	metrics.CountFailure(string(category))
+
+	return category
+}
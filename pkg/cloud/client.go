@@ -0,0 +1,439 @@
+// Package cloud is a minimal client for the Containership Cloud and
+// provision APIs, covering only the operations the e2e suites need.
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/environment"
+	"github.com/containership/e2e-test/pkg/log"
+)
+
+// Client talks to the Containership Cloud APIs on behalf of the e2e suites.
+type Client struct {
+	httpClient *http.Client
+
+	apiBaseURL       string
+	provisionBaseURL string
+	token            string
+	organizationID   string
+}
+
+// New constructs a Client from the environment and the shared config file.
+// It returns an error if the required token or organization ID is unset, or
+// if the target environment cannot be resolved.
+func New() (*Client, error) {
+	token, err := config.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, errors.Errorf("%s must be set (or a token configured via --config)", constants.TokenEnvVar)
+	}
+
+	organizationID := environment.OrganizationID()
+	if organizationID == "" {
+		return nil, errors.Errorf("%s must be set", constants.OrganizationIDEnvVar)
+	}
+
+	env, err := environment.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		apiBaseURL:       env.APIBaseURL,
+		provisionBaseURL: env.ProvisionBaseURL,
+		token:            token,
+		organizationID:   organizationID,
+	}, nil
+}
+
+// OrganizationID returns the ID of the organization this client operates on.
+func (c *Client) OrganizationID() string {
+	return c.organizationID
+}
+
+// CreateTemplate creates a provision template in the organization.
+func (c *Client) CreateTemplate(req *CreateTemplateRequest) (*Template, error) {
+	var template Template
+	url := fmt.Sprintf("%s/v3/organizations/%s/templates", c.provisionBaseURL, c.organizationID)
+	if err := c.do(http.MethodPost, url, req, &template); err != nil {
+		return nil, errors.Wrap(err, "creating template")
+	}
+
+	return &template, nil
+}
+
+// GetTemplate gets a single template by ID.
+func (c *Client) GetTemplate(templateID string) (*Template, error) {
+	var template Template
+	url := fmt.Sprintf("%s/v3/organizations/%s/templates/%s", c.provisionBaseURL, c.organizationID, templateID)
+	if err := c.do(http.MethodGet, url, nil, &template); err != nil {
+		return nil, errors.Wrapf(err, "getting template %s", templateID)
+	}
+
+	return &template, nil
+}
+
+// ListTemplates lists all templates in the organization.
+func (c *Client) ListTemplates() ([]Template, error) {
+	var templates []Template
+	url := fmt.Sprintf("%s/v3/organizations/%s/templates", c.provisionBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &templates); err != nil {
+		return nil, errors.Wrap(err, "listing templates")
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate deletes a template by ID.
+func (c *Client) DeleteTemplate(templateID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/templates/%s", c.provisionBaseURL, c.organizationID, templateID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting template %s", templateID)
+}
+
+// CreateCluster provisions a new cluster from a template.
+func (c *Client) CreateCluster(req *CreateClusterRequest) (*Cluster, error) {
+	var cluster Cluster
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters", c.provisionBaseURL, c.organizationID)
+	if err := c.do(http.MethodPost, url, req, &cluster); err != nil {
+		return nil, errors.Wrap(err, "creating cluster")
+	}
+
+	return &cluster, nil
+}
+
+// GetCluster gets a single cluster by ID.
+func (c *Client) GetCluster(clusterID string) (*Cluster, error) {
+	var cluster Cluster
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s", c.provisionBaseURL, c.organizationID, clusterID)
+	if err := c.do(http.MethodGet, url, nil, &cluster); err != nil {
+		return nil, errors.Wrapf(err, "getting cluster %s", clusterID)
+	}
+
+	return &cluster, nil
+}
+
+// ListClusters lists all clusters in the organization.
+func (c *Client) ListClusters() ([]Cluster, error) {
+	var clusters []Cluster
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters", c.provisionBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &clusters); err != nil {
+		return nil, errors.Wrap(err, "listing clusters")
+	}
+
+	return clusters, nil
+}
+
+// DeleteCluster deletes a cluster by ID.
+func (c *Client) DeleteCluster(clusterID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s", c.provisionBaseURL, c.organizationID, clusterID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting cluster %s", clusterID)
+}
+
+// ForceDeleteCluster deletes a cluster with the force flag, telling the
+// provision API to tear down provider resources without waiting for
+// graceful in-cluster teardown. Only used as an escalation after a normal
+// delete has timed out.
+func (c *Client) ForceDeleteCluster(clusterID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s?force=true", c.provisionBaseURL, c.organizationID, clusterID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "force-deleting cluster %s", clusterID)
+}
+
+// GetClusterCredentials fetches the cluster's direct API endpoint and
+// credentials, for falling back past a degraded Kubernetes proxy. Not all
+// providers expose them; absent fields come back empty.
+func (c *Client) GetClusterCredentials(clusterID string) (*ClusterCredentials, error) {
+	var credentials ClusterCredentials
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/credentials", c.provisionBaseURL, c.organizationID, clusterID)
+	if err := c.do(http.MethodGet, url, nil, &credentials); err != nil {
+		return nil, errors.Wrapf(err, "getting credentials for cluster %s", clusterID)
+	}
+
+	return &credentials, nil
+}
+
+// ListProviderLinks lists the provider credentials linked to the
+// organization.
+func (c *Client) ListProviderLinks() ([]ProviderLink, error) {
+	var links []ProviderLink
+	url := fmt.Sprintf("%s/v3/organizations/%s/providers", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &links); err != nil {
+		return nil, errors.Wrap(err, "listing provider links")
+	}
+
+	return links, nil
+}
+
+// ListNodePools lists the node pools of a cluster.
+func (c *Client) ListNodePools(clusterID string) ([]NodePool, error) {
+	var nodePools []NodePool
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools", c.provisionBaseURL, c.organizationID, clusterID)
+	if err := c.do(http.MethodGet, url, nil, &nodePools); err != nil {
+		return nil, errors.Wrapf(err, "listing node pools for cluster %s", clusterID)
+	}
+
+	return nodePools, nil
+}
+
+// CreateNodePool creates a brand-new node pool on an existing cluster. The
+// pool provisions asynchronously; poll it with WaitForNodePoolRunning.
+func (c *Client) CreateNodePool(clusterID string, req *CreateNodePoolRequest) (*NodePool, error) {
+	var nodePool NodePool
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools", c.provisionBaseURL, c.organizationID, clusterID)
+	if err := c.do(http.MethodPost, url, req, &nodePool); err != nil {
+		return nil, errors.Wrapf(err, "creating node pool on cluster %s", clusterID)
+	}
+
+	return &nodePool, nil
+}
+
+// DeleteNodePool deletes a node pool and its nodes from a cluster.
+func (c *Client) DeleteNodePool(clusterID, nodePoolID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting node pool %s", nodePoolID)
+}
+
+// ScaleNodePool sets the target node count of a node pool. The pool
+// transitions through scaling back to running once the new nodes join (or
+// drained nodes leave).
+func (c *Client) ScaleNodePool(clusterID, nodePoolID string, count int32) error {
+	body := map[string]int32{"count": count}
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	return errors.Wrapf(c.do(http.MethodPatch, url, body, nil), "scaling node pool %s to %d", nodePoolID, count)
+}
+
+// SetNodePoolLabels replaces the custom labels of a node pool. The label
+// sync controller propagates them to the pool's Kubernetes nodes; passing
+// an empty map clears them.
+func (c *Client) SetNodePoolLabels(clusterID, nodePoolID string, labels map[string]string) error {
+	body := map[string]interface{}{"labels": labels}
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	return errors.Wrapf(c.do(http.MethodPatch, url, body, nil), "setting labels on node pool %s", nodePoolID)
+}
+
+// SetNodePoolTaints replaces the taints of a node pool. The sync controller
+// applies them to the pool's Kubernetes nodes; passing an empty slice
+// clears them.
+func (c *Client) SetNodePoolTaints(clusterID, nodePoolID string, taints []Taint) error {
+	body := map[string]interface{}{"taints": taints}
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	return errors.Wrapf(c.do(http.MethodPatch, url, body, nil), "setting taints on node pool %s", nodePoolID)
+}
+
+// ListNodes lists the nodes of a node pool.
+func (c *Client) ListNodes(clusterID, nodePoolID string) ([]Node, error) {
+	var nodes []Node
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s/nodes", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	if err := c.do(http.MethodGet, url, nil, &nodes); err != nil {
+		return nil, errors.Wrapf(err, "listing nodes for node pool %s", nodePoolID)
+	}
+
+	return nodes, nil
+}
+
+// DeleteNode terminates a single node of a node pool. The pool keeps its
+// target count, so the provision API replaces the terminated node; the
+// pool transitions through updating while the replacement provisions.
+func (c *Client) DeleteNode(clusterID, nodePoolID, nodeID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s/nodes/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID, nodeID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting node %s", nodeID)
+}
+
+// GetNodePool gets a single node pool of a cluster by ID.
+func (c *Client) GetNodePool(clusterID, nodePoolID string) (*NodePool, error) {
+	var nodePool NodePool
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	if err := c.do(http.MethodGet, url, nil, &nodePool); err != nil {
+		return nil, errors.Wrapf(err, "getting node pool %s", nodePoolID)
+	}
+
+	return &nodePool, nil
+}
+
+// CreateWebhook registers an org webhook for lifecycle events.
+func (c *Client) CreateWebhook(req *CreateWebhookRequest) (*Webhook, error) {
+	var webhook Webhook
+	url := fmt.Sprintf("%s/v3/organizations/%s/webhooks", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodPost, url, req, &webhook); err != nil {
+		return nil, errors.Wrap(err, "creating webhook")
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhooks lists the organization's webhook subscriptions.
+func (c *Client) ListWebhooks() ([]Webhook, error) {
+	var webhooks []Webhook
+	url := fmt.Sprintf("%s/v3/organizations/%s/webhooks", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &webhooks); err != nil {
+		return nil, errors.Wrap(err, "listing webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes an org webhook by ID.
+func (c *Client) DeleteWebhook(webhookID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/webhooks/%s", c.apiBaseURL, c.organizationID, webhookID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting webhook %s", webhookID)
+}
+
+// WithToken returns a copy of the client that authenticates with the given
+// token instead, for exercising scoped API keys against the same org.
+func (c *Client) WithToken(token string) *Client {
+	scoped := *c
+	scoped.token = token
+	return &scoped
+}
+
+// CreateAPIKey creates a scoped API key in the organization. The returned
+// key's Token is only available in this response.
+func (c *Client) CreateAPIKey(req *CreateAPIKeyRequest) (*APIKey, error) {
+	var key APIKey
+	url := fmt.Sprintf("%s/v3/organizations/%s/api-keys", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodPost, url, req, &key); err != nil {
+		return nil, errors.Wrap(err, "creating API key")
+	}
+
+	return &key, nil
+}
+
+// DeleteAPIKey deletes (revokes) an API key by ID.
+func (c *Client) DeleteAPIKey(keyID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/api-keys/%s", c.apiBaseURL, c.organizationID, keyID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting API key %s", keyID)
+}
+
+// SSHKeyNamePrefix marks SSH keys uploaded by e2e runs. Keys carry no
+// owner information, so this prefix is the only way cleanup and sweeping
+// can tell ours apart from keys humans care about.
+const SSHKeyNamePrefix = "e2e-"
+
+// CreateSSHKey uploads an SSH public key to the organization. The name
+// must carry SSHKeyNamePrefix so cleanup can find the key later.
+func (c *Client) CreateSSHKey(req *CreateSSHKeyRequest) (*SSHKey, error) {
+	var key SSHKey
+	url := fmt.Sprintf("%s/v3/organizations/%s/ssh-keys", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodPost, url, req, &key); err != nil {
+		return nil, errors.Wrap(err, "creating SSH key")
+	}
+
+	return &key, nil
+}
+
+// ListSSHKeys lists all SSH keys in the organization.
+func (c *Client) ListSSHKeys() ([]SSHKey, error) {
+	var keys []SSHKey
+	url := fmt.Sprintf("%s/v3/organizations/%s/ssh-keys", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &keys); err != nil {
+		return nil, errors.Wrap(err, "listing SSH keys")
+	}
+
+	return keys, nil
+}
+
+// DeleteSSHKey deletes an SSH key by ID.
+func (c *Client) DeleteSSHKey(keyID string) error {
+	url := fmt.Sprintf("%s/v3/organizations/%s/ssh-keys/%s", c.apiBaseURL, c.organizationID, keyID)
+	return errors.Wrapf(c.do(http.MethodDelete, url, nil, nil), "deleting SSH key %s", keyID)
+}
+
+// DeleteOwnedSSHKeys deletes every SSH key in the organization carrying
+// SSHKeyNamePrefix and returns the names deleted. Runs are serialized by
+// the org lock, so any e2e-prefixed key present during cleanup is either
+// ours or an accumulated leftover from an earlier run.
+func (c *Client) DeleteOwnedSSHKeys() ([]string, error) {
+	keys, err := c.ListSSHKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key.Name, SSHKeyNamePrefix) {
+			continue
+		}
+		if err := c.DeleteSSHKey(key.ID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, key.Name)
+	}
+
+	return deleted, nil
+}
+
+// do performs a request against the given URL, marshaling body (if non-nil)
+// as JSON and unmarshaling the response into out (if non-nil).
+func (c *Client) do(method, url string, body interface{}, out interface{}) error {
+	reqBody := bytes.NewBuffer(nil)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "marshaling request body")
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("JWT %s", c.token))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Trace every API round trip at debug level so a run with
+	// E2E_LOG_LEVEL=debug yields a full HTTP transcript in the run log.
+	requestStart := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		observeCall(method, url, time.Since(requestStart), true)
+		log.Debugf("cloud API %s %s failed after %s: %v",
+			method, url, time.Since(requestStart).Truncate(time.Millisecond), err)
+		return errors.Wrapf(err, "%s %s", method, url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		observeCall(method, url, time.Since(requestStart), true)
+		return errors.Wrap(err, "reading response body")
+	}
+
+	failed := resp.StatusCode < 200 || resp.StatusCode >= 300
+	observeCall(method, url, time.Since(requestStart), failed)
+	log.Debugf("cloud API %s %s -> %d (%d bytes in %s)",
+		method, url, resp.StatusCode, len(respBody), time.Since(requestStart).Truncate(time.Millisecond))
+
+	if failed {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Method:     method,
+			URL:        url,
+			Body:       string(respBody),
+		}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return errors.Wrap(err, "unmarshaling response body")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newFakeProvisionServer serves a fixed set of node pools so client paths
+// can be benchmarked without real cloud calls.
+func newFakeProvisionServer(nodePoolCount int) *httptest.Server {
+	nodePools := make([]NodePool, nodePoolCount)
+	for i := range nodePools {
+		nodePools[i] = NodePool{
+			ID:                fmt.Sprintf("pool-%d", i),
+			Name:              fmt.Sprintf("worker-pool-%d", i),
+			ClusterID:         "cluster-1",
+			KubernetesMode:    "worker",
+			KubernetesVersion: "1.15.3",
+			Count:             3,
+			Status:            Status{Type: NodePoolStatusRunning},
+		}
+	}
+	body, err := json.Marshal(nodePools)
+	if err != nil {
+		panic(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		//nolint:errcheck
+		w.Write(body)
+	}))
+}
+
+// BenchmarkListNodePools guards the request/unmarshal path hit in every
+// poll loop against performance regressions during framework refactors.
+func BenchmarkListNodePools(b *testing.B) {
+	server := newFakeProvisionServer(10)
+	defer server.Close()
+
+	client := &Client{
+		httpClient:       &http.Client{Timeout: 10 * time.Second},
+		apiBaseURL:       server.URL,
+		provisionBaseURL: server.URL,
+		token:            "benchmark-token",
+		organizationID:   "benchmark-org",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ListNodePools("cluster-1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Raw getters for contract tests: same endpoints as the typed getters, but
+// the response body is kept verbatim so it can be strict-decoded against
+// the client types.
+
+// GetClusterRaw fetches a cluster as raw JSON.
+func (c *Client) GetClusterRaw(clusterID string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s", c.provisionBaseURL, c.organizationID, clusterID)
+	if err := c.do(http.MethodGet, url, nil, &raw); err != nil {
+		return nil, errors.Wrapf(err, "getting raw cluster %s", clusterID)
+	}
+
+	return raw, nil
+}
+
+// GetNodePoolRaw fetches a node pool as raw JSON.
+func (c *Client) GetNodePoolRaw(clusterID, nodePoolID string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/node-pools/%s", c.provisionBaseURL, c.organizationID, clusterID, nodePoolID)
+	if err := c.do(http.MethodGet, url, nil, &raw); err != nil {
+		return nil, errors.Wrapf(err, "getting raw node pool %s", nodePoolID)
+	}
+
+	return raw, nil
+}
+
+// GetTemplateRaw fetches a template as raw JSON.
+func (c *Client) GetTemplateRaw(templateID string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	url := fmt.Sprintf("%s/v3/organizations/%s/templates/%s", c.provisionBaseURL, c.organizationID, templateID)
+	if err := c.do(http.MethodGet, url, nil, &raw); err != nil {
+		return nil, errors.Wrapf(err, "getting raw template %s", templateID)
+	}
+
+	return raw, nil
+}
+
+// StrictDecode unmarshals data into out, erroring on any field the target
+// type doesn't declare. The regular client tolerates unknown fields by
+// design; contract tests use this to notice when the backend starts
+// sending something the client types silently drop.
+func StrictDecode(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return errors.Wrap(decoder.Decode(out), "strict decode")
+}
+
+// MissingFields reports top-level JSON keys that re-marshaling decoded
+// produces but the API response lacks. Keys marked omitempty only appear
+// when set, so anything reported here is a field the client types treat as
+// always-present that the backend has stopped sending.
+func MissingFields(response []byte, decoded interface{}) ([]string, error) {
+	var responseKeys map[string]json.RawMessage
+	if err := json.Unmarshal(response, &responseKeys); err != nil {
+		return nil, errors.Wrap(err, "parsing response object")
+	}
+
+	remarshaled, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "re-marshaling decoded object")
+	}
+	var decodedKeys map[string]json.RawMessage
+	if err := json.Unmarshal(remarshaled, &decodedKeys); err != nil {
+		return nil, errors.Wrap(err, "parsing re-marshaled object")
+	}
+
+	var missing []string
+	for key := range decodedKeys {
+		if _, ok := responseKeys[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
@@ -0,0 +1,46 @@
+package cloud
+
+import (
+	"fmt"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+)
+
+// DumpState snapshots the cloud-side state of the cluster under test into
+// the artifacts directory. It GETs the cluster, its node pools, and the
+// template it was provisioned from, and writes the full JSON of each so a
+// failure can be diagnosed against the exact cloud state at failure time.
+// Errors are returned but safe to ignore from failure handlers; a partial
+// dump is better than none.
+func (c *Client) DumpState(clusterID, templateID string) error {
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if clusterID != "" {
+		cluster, err := c.GetCluster(clusterID)
+		record(err)
+		if err == nil {
+			record(artifacts.WriteJSON(fmt.Sprintf("cluster-%s.json", clusterID), cluster))
+		}
+
+		nodePools, err := c.ListNodePools(clusterID)
+		record(err)
+		if err == nil {
+			record(artifacts.WriteJSON(fmt.Sprintf("cluster-%s-node-pools.json", clusterID), nodePools))
+		}
+	}
+
+	if templateID != "" {
+		template, err := c.GetTemplate(templateID)
+		record(err)
+		if err == nil {
+			record(artifacts.WriteJSON(fmt.Sprintf("template-%s.json", templateID), template))
+		}
+	}
+
+	return firstErr
+}
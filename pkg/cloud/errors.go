@@ -0,0 +1,97 @@
+package cloud
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// APIError is returned when the cloud APIs respond with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// ClusterErrorStateError is returned when a cluster being waited on enters
+// the error state.
+type ClusterErrorStateError struct {
+	ClusterID string
+	Message   string
+}
+
+// Error implements the error interface.
+func (e *ClusterErrorStateError) Error() string {
+	return fmt.Sprintf("cluster %s entered error state: %s", e.ClusterID, e.Message)
+}
+
+// NodePoolErrorStateError is returned when a node pool being waited on
+// enters the error state. NodeMessages carries the per-node error details
+// fetched at diagnosis time, so the failure names the node-level cause
+// rather than just "error".
+type NodePoolErrorStateError struct {
+	ClusterID    string
+	NodePoolID   string
+	Message      string
+	NodeMessages []string
+}
+
+// Error implements the error interface.
+func (e *NodePoolErrorStateError) Error() string {
+	msg := fmt.Sprintf("node pool %s entered error state: %s", e.NodePoolID, e.Message)
+	if len(e.NodeMessages) > 0 {
+		msg += fmt.Sprintf(" (node errors: %s)", strings.Join(e.NodeMessages, "; "))
+	}
+	return msg
+}
+
+// retryableProviderMessages are substrings of provider error messages that
+// indicate a transient condition worth retrying with a fresh cluster.
+var retryableProviderMessages = []string{
+	"capacity",
+	"quota",
+	"rate limit",
+	"too many requests",
+	"temporarily unavailable",
+	"try again",
+}
+
+// IsRetryableProvisionError returns true if a provisioning failure looks
+// transient (provider capacity, transient API errors) such that retrying
+// the entire provision with a fresh cluster is likely to succeed.
+func IsRetryableProvisionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+	}
+
+	var stateErr *ClusterErrorStateError
+	if errors.As(err, &stateErr) {
+		message := strings.ToLower(stateErr.Message)
+		for _, substr := range retryableProviderMessages {
+			if strings.Contains(message, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
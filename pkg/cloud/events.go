@@ -0,0 +1,56 @@
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Event types emitted by the platform's audit trail for the operations the
+// suites perform.
+const (
+	EventClusterCreated = "cluster.created"
+	EventClusterDeleted = "cluster.deleted"
+	EventNodePoolScaled = "node_pool.scaled"
+)
+
+// Event is a single org activity/audit event.
+type Event struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	// ActorID identifies the account or API key that performed the action.
+	ActorID   string `json:"actor_id,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// ListEvents lists the organization's activity events, newest first.
+func (c *Client) ListEvents() ([]Event, error) {
+	var events []Event
+	url := fmt.Sprintf("%s/v3/organizations/%s/events", c.apiBaseURL, c.organizationID)
+	if err := c.do(http.MethodGet, url, nil, &events); err != nil {
+		return nil, errors.Wrap(err, "listing events")
+	}
+
+	return events, nil
+}
+
+// FindEvent returns the first event matching the given type and resource
+// ID, or nil if none matches.
+func FindEvent(events []Event, eventType, resourceID string) *Event {
+	for i := range events {
+		if events[i].Type == eventType && events[i].ResourceID == resourceID {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// IsNotFound returns true if the error is an API 404, used to detect
+// endpoints (like the events API) that a given environment doesn't offer.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,113 @@
+package cloud
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/metrics"
+)
+
+// EndpointStats are the per-endpoint call statistics accumulated over a
+// run. Errors count transport failures and non-2xx responses alike: both
+// look like a flaky endpoint from the harness's perspective.
+type EndpointStats struct {
+	Calls  int `json:"calls"`
+	Errors int `json:"errors"`
+
+	TotalSeconds float64 `json:"total_seconds"`
+	MeanSeconds  float64 `json:"mean_seconds"`
+	MaxSeconds   float64 `json:"max_seconds"`
+	ErrorRate    float64 `json:"error_rate"`
+}
+
+// Every API call funnels through do, so one observation point covers all
+// endpoints, present and future.
+var (
+	statsMu sync.Mutex
+	stats   = map[string]*EndpointStats{}
+)
+
+func init() {
+	// Export alongside the run metrics: any suite flushing metrics also
+	// gets the API stats artifact.
+	metrics.OnFlush(writeStatsArtifact)
+}
+
+// observeCall records one API round trip against its normalized endpoint.
+func observeCall(method, rawURL string, elapsed time.Duration, failed bool) {
+	endpoint := method + " " + normalizeEndpoint(rawURL)
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	s := stats[endpoint]
+	if s == nil {
+		s = &EndpointStats{}
+		stats[endpoint] = s
+	}
+
+	s.Calls++
+	if failed {
+		s.Errors++
+	}
+	seconds := elapsed.Seconds()
+	s.TotalSeconds += seconds
+	if seconds > s.MaxSeconds {
+		s.MaxSeconds = seconds
+	}
+}
+
+// idCollections are path segments whose following segment is a resource ID.
+var idCollections = map[string]bool{
+	"organizations": true,
+	"templates":     true,
+	"clusters":      true,
+	"node-pools":    true,
+	"nodes":         true,
+	"webhooks":      true,
+	"api-keys":      true,
+	"ssh-keys":      true,
+}
+
+// normalizeEndpoint reduces a concrete request URL to its endpoint shape by
+// replacing resource IDs with :id, so calls against different clusters and
+// pools aggregate together.
+func normalizeEndpoint(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	segments := strings.Split(parsed.Path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if idCollections[segments[i]] && segments[i+1] != "" {
+			segments[i+1] = ":id"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// writeStatsArtifact derives the mean and error rate per endpoint and
+// writes the snapshot as an artifact next to the run metrics.
+func writeStatsArtifact() {
+	statsMu.Lock()
+	snapshot := make(map[string]EndpointStats, len(stats))
+	for endpoint, s := range stats {
+		derived := *s
+		derived.MeanSeconds = derived.TotalSeconds / float64(derived.Calls)
+		derived.ErrorRate = float64(derived.Errors) / float64(derived.Calls)
+		snapshot[endpoint] = derived
+	}
+	statsMu.Unlock()
+
+	if len(snapshot) == 0 {
+		return
+	}
+
+	//nolint:errcheck - stats export must not mask the metrics flush result
+	artifacts.WriteJSON("cloud-api-stats.json", snapshot)
+}
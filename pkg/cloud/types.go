@@ -0,0 +1,212 @@
+package cloud
+
+import "encoding/json"
+
+// Cluster status type values reported by the provision API.
+const (
+	ClusterStatusProvisioning = "provisioning"
+	ClusterStatusRunning      = "running"
+	ClusterStatusDeleting     = "deleting"
+	ClusterStatusError        = "error"
+)
+
+// Node pool status type values reported by the provision API.
+const (
+	NodePoolStatusProvisioning = "provisioning"
+	NodePoolStatusRunning      = "running"
+	NodePoolStatusScaling      = "scaling"
+	NodePoolStatusUpdating     = "updating"
+	NodePoolStatusDeleting     = "deleting"
+	NodePoolStatusError        = "error"
+)
+
+// Node status type values reported by the provision API.
+const (
+	NodeStatusRunning = "running"
+	NodeStatusError   = "error"
+)
+
+// Status is the status object attached to clusters and node pools.
+type Status struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	Percent string `json:"percent,omitempty"`
+}
+
+// Cluster is a CKE cluster as returned by the provision API.
+type Cluster struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	OwnerID           string            `json:"owner_id,omitempty"`
+	OrganizationID    string            `json:"organization_id,omitempty"`
+	TemplateID        string            `json:"template_id,omitempty"`
+	ProviderName      string            `json:"provider_name,omitempty"`
+	Environment       string            `json:"environment,omitempty"`
+	KubernetesVersion string            `json:"kubernetes_version,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	APIServerAddress  string            `json:"api_server_address,omitempty"`
+	Status            Status            `json:"status"`
+	CreatedAt         string            `json:"created_at,omitempty"`
+	UpdatedAt         string            `json:"updated_at,omitempty"`
+}
+
+// NodePool is a node pool belonging to a CKE cluster.
+type NodePool struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	ClusterID         string `json:"cluster_id,omitempty"`
+	KubernetesMode    string `json:"kubernetes_mode,omitempty"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	InstanceSize      string `json:"instance_size,omitempty"`
+	Count             int32  `json:"count"`
+	// Labels are custom labels the label sync controller propagates to the
+	// pool's Kubernetes nodes.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Taints are taints the sync controller applies to the pool's
+	// Kubernetes nodes.
+	Taints    []Taint `json:"taints,omitempty"`
+	Status    Status  `json:"status"`
+	CreatedAt string  `json:"created_at,omitempty"`
+	UpdatedAt string  `json:"updated_at,omitempty"`
+}
+
+// CreateNodePoolRequest is the request body for creating a node pool on an
+// existing cluster. Empty InstanceSize and KubernetesVersion fall back to
+// the provider default and the cluster's version respectively.
+type CreateNodePoolRequest struct {
+	Name              string `json:"name"`
+	KubernetesMode    string `json:"kubernetes_mode"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	InstanceSize      string `json:"instance_size,omitempty"`
+	Count             int32  `json:"count"`
+}
+
+// Taint is a Kubernetes node taint configured on a node pool.
+type Taint struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	// Effect is one of NoSchedule, PreferNoSchedule, or NoExecute.
+	Effect string `json:"effect"`
+}
+
+// Node is a single node belonging to a node pool, as tracked by the
+// provision API.
+type Node struct {
+	ID         string `json:"id"`
+	NodePoolID string `json:"node_pool_id,omitempty"`
+	// InstanceID is the provider-side instance identifier, which also
+	// appears on the corresponding Kubernetes node's labels.
+	InstanceID string `json:"instance_id,omitempty"`
+	Status     Status `json:"status"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+// IsMaster returns true if this node pool runs master nodes.
+func (np *NodePool) IsMaster() bool {
+	return np.KubernetesMode == "master"
+}
+
+// ClusterCredentials are the direct (non-proxied) access credentials for a
+// cluster's API server, for providers that expose them. Fields the
+// provider doesn't support are empty.
+type ClusterCredentials struct {
+	// APIServerAddress is the direct URL of the cluster's API server.
+	APIServerAddress string `json:"api_server_address,omitempty"`
+	// Token is a bearer token accepted by the API server directly.
+	Token string `json:"token,omitempty"`
+	// CACertificate is the PEM-encoded CA bundle the API server's serving
+	// certificate chains to.
+	CACertificate string `json:"ca_certificate,omitempty"`
+}
+
+// ProviderLink is a set of provider credentials linked to the
+// organization. Linking requires the provider-side secret, so the e2e
+// tooling only ever reads these to verify the org is ready to provision.
+type ProviderLink struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Provider  string `json:"provider"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// Webhook is an org webhook subscription for lifecycle events.
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events are the event types delivered to this webhook; empty means all.
+	Events    []string `json:"events,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
+// CreateWebhookRequest is the request body for registering a webhook.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
+// APIKey is a scoped API key (service account credential) in the
+// organization. Token is only populated in the create response.
+type APIKey struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles,omitempty"`
+	Token     string   `json:"token,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
+// CreateAPIKeyRequest is the request body for creating an API key.
+type CreateAPIKeyRequest struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// SSHKey is an SSH public key uploaded to the organization. The API does
+// not record who uploaded a key, so e2e-owned keys are identified purely by
+// the SSHKeyNamePrefix naming convention.
+type SSHKey struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	PublicKey   string `json:"public_key,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+// CreateSSHKeyRequest is the request body for uploading an SSH key.
+type CreateSSHKeyRequest struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
+
+// Template is a provision template from which clusters are created. The
+// provider-specific configuration and variable map are kept raw since the
+// suites only pass them through to the provision API.
+type Template struct {
+	ID            string          `json:"id"`
+	ProviderName  string          `json:"provider_name"`
+	Description   string          `json:"description,omitempty"`
+	Engine        string          `json:"engine,omitempty"`
+	Configuration json.RawMessage `json:"configuration,omitempty"`
+	CreatedAt     string          `json:"created_at,omitempty"`
+	UpdatedAt     string          `json:"updated_at,omitempty"`
+}
+
+// CreateTemplateRequest is the request body for creating a template.
+type CreateTemplateRequest struct {
+	ProviderName  string          `json:"provider_name"`
+	Description   string          `json:"description,omitempty"`
+	Engine        string          `json:"engine,omitempty"`
+	Configuration json.RawMessage `json:"configuration"`
+}
+
+// CreateClusterRequest is the request body for provisioning a cluster from
+// an existing template.
+type CreateClusterRequest struct {
+	TemplateID  string            `json:"template_id"`
+	Name        string            `json:"name"`
+	Environment string            `json:"environment,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// ProviderOptions are provider-specific create options; when nil the
+	// provider defaults apply.
+	ProviderOptions json.RawMessage `json:"provider_options,omitempty"`
+}
@@ -0,0 +1,29 @@
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// UpgradeCluster requests an in-place upgrade of the cluster to the given
+// Kubernetes version. The cluster transitions back to provisioning until
+// the upgrade completes.
+func (c *Client) UpgradeCluster(clusterID, kubernetesVersion string) error {
+	body := map[string]string{"kubernetes_version": kubernetesVersion}
+	url := fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/upgrade", c.provisionBaseURL, c.organizationID, clusterID)
+	return errors.Wrapf(c.do(http.MethodPost, url, body, nil), "upgrading cluster %s to %s", clusterID, kubernetesVersion)
+}
+
+// ListSupportedKubernetesVersions returns the Kubernetes versions the
+// provision API currently supports for new clusters.
+func (c *Client) ListSupportedKubernetesVersions() ([]string, error) {
+	var versions []string
+	url := fmt.Sprintf("%s/v3/kubernetes-versions", c.provisionBaseURL)
+	if err := c.do(http.MethodGet, url, nil, &versions); err != nil {
+		return nil, errors.Wrap(err, "listing supported Kubernetes versions")
+	}
+
+	return versions, nil
+}
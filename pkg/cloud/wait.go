@@ -0,0 +1,424 @@
+package cloud
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/log"
+)
+
+var strictStates = flag.Bool("strict-states", false,
+	"fail waits immediately on unrecognized cluster or node pool states instead of warning and continuing")
+
+// StuckStateThreshold is how long a cluster or node pool may report the
+// exact same status before the wait helpers treat it as stuck and collect
+// diagnostics, well before the overall timeout fires.
+const StuckStateThreshold = 10 * time.Minute
+
+// stuckDetector watches a stream of status observations during a wait and
+// triggers a diagnostics dump the first time the status goes unchanged for
+// StuckStateThreshold. It re-arms whenever the status changes, so a wait
+// that gets stuck twice in different states dumps both.
+type stuckDetector struct {
+	client    *Client
+	clusterID string
+	// what identifies the waited-on object in artifact names, e.g.
+	// "cluster-abc" or "node-pool-def".
+	what string
+
+	last       Status
+	lastChange time.Time
+	triggered  bool
+}
+
+func newStuckDetector(client *Client, clusterID, what string) *stuckDetector {
+	return &stuckDetector{
+		client:     client,
+		clusterID:  clusterID,
+		what:       what,
+		lastChange: time.Now(),
+	}
+}
+
+// observe records a status sample and collects diagnostics if the status
+// has not changed within StuckStateThreshold. Diagnostics are best-effort;
+// the wait loop continues either way.
+func (d *stuckDetector) observe(status Status) {
+	if status != d.last {
+		d.last = status
+		d.lastChange = time.Now()
+		d.triggered = false
+		return
+	}
+
+	stuckFor := time.Since(d.lastChange)
+	if d.triggered || stuckFor < StuckStateThreshold {
+		return
+	}
+	d.triggered = true
+
+	// Capture cloud-side state while the problem is live rather than after
+	// the timeout, when the most useful data may already be gone.
+	//nolint:errcheck - diagnostics must not disturb the wait
+	artifacts.WriteJSON(fmt.Sprintf("stuck-%s.json", d.what), map[string]interface{}{
+		"status":        status,
+		"stuck_minutes": int(stuckFor.Minutes()),
+	})
+	//nolint:errcheck
+	d.client.DumpState(d.clusterID, "")
+}
+
+// stateTracker classifies the status types seen during a wait. The wait
+// helpers only recognize the states declared in types.go, but the backend
+// grows transitional sub-states faster than the harness learns about them,
+// so states listed under transitional_states in the config file are
+// accepted as in-flight too. A state recognized by neither is a one-time
+// warning with diagnostics by default — the wait keeps polling and the
+// timeout remains the backstop — or an immediate failure under
+// --strict-states, for runs that want to catch new states loudly.
+type stateTracker struct {
+	client    *Client
+	clusterID string
+	// what identifies the waited-on object in messages and artifact
+	// names, mirroring stuckDetector.
+	what string
+
+	known  map[string]bool
+	warned map[string]bool
+}
+
+func newStateTracker(client *Client, clusterID, what string, transitional ...string) *stateTracker {
+	t := &stateTracker{
+		client:    client,
+		clusterID: clusterID,
+		what:      what,
+		known:     make(map[string]bool),
+		warned:    make(map[string]bool),
+	}
+	for _, state := range transitional {
+		t.known[state] = true
+	}
+	for _, state := range config.TransitionalStates() {
+		t.known[state] = true
+	}
+	return t
+}
+
+// check vets a non-terminal status sample. Terminal states (running,
+// error) never reach here; the wait loops handle those before consulting
+// the tracker.
+func (t *stateTracker) check(status Status) error {
+	if t.known[status.Type] {
+		return nil
+	}
+
+	if *strictStates {
+		//nolint:errcheck - diagnostics are best-effort on the failure path
+		t.client.DumpState(t.clusterID, "")
+		return errors.Errorf("%s reported unrecognized state %q (message: %q); add it to transitional_states or drop --strict-states to tolerate it",
+			t.what, status.Type, status.Message)
+	}
+
+	if t.warned[status.Type] {
+		return nil
+	}
+	t.warned[status.Type] = true
+
+	log.Warnf("%s reported unrecognized state %q; treating it as transitional (add it to transitional_states in the config file to silence this)",
+		t.what, status.Type)
+	//nolint:errcheck - diagnostics must not disturb the wait
+	artifacts.WriteJSON(fmt.Sprintf("unknown-state-%s.json", t.what), map[string]interface{}{
+		"status": status,
+	})
+	//nolint:errcheck
+	t.client.DumpState(t.clusterID, "")
+
+	return nil
+}
+
+// WaitForClusterRunning polls the cluster until it reaches the running
+// state, returning an error if it enters the error state or the timeout
+// elapses.
+func (c *Client) WaitForClusterRunning(clusterID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	what := fmt.Sprintf("cluster-%s", clusterID)
+	stuck := newStuckDetector(c, clusterID, what)
+	states := newStateTracker(c, clusterID, what,
+		ClusterStatusProvisioning, ClusterStatusDeleting)
+
+	for {
+		cluster, err := c.GetCluster(clusterID)
+		if err != nil {
+			return err
+		}
+		stuck.observe(cluster.Status)
+
+		switch cluster.Status.Type {
+		case ClusterStatusRunning:
+			return nil
+		case ClusterStatusError:
+			return &ClusterErrorStateError{
+				ClusterID: clusterID,
+				Message:   cluster.Status.Message,
+			}
+		default:
+			if err := states.check(cluster.Status); err != nil {
+				return err
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for cluster %s to be running (last status: %s)", clusterID, cluster.Status.Type)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
+
+// WaitForNodePoolRunning polls the node pool until it reaches the running
+// state, returning an error if it enters the error state or the timeout
+// elapses.
+func (c *Client) WaitForNodePoolRunning(clusterID, nodePoolID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	what := fmt.Sprintf("node-pool-%s", nodePoolID)
+	stuck := newStuckDetector(c, clusterID, what)
+	states := newStateTracker(c, clusterID, what,
+		NodePoolStatusProvisioning, NodePoolStatusScaling, NodePoolStatusUpdating, NodePoolStatusDeleting)
+
+	for {
+		nodePool, err := c.GetNodePool(clusterID, nodePoolID)
+		if err != nil {
+			return err
+		}
+		stuck.observe(nodePool.Status)
+
+		switch nodePool.Status.Type {
+		case NodePoolStatusRunning:
+			return nil
+		case NodePoolStatusError:
+			return c.handleNodePoolErrorState(clusterID, nodePool)
+		default:
+			if err := states.check(nodePool.Status); err != nil {
+				return err
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for node pool %s to be running (last status: %s)", nodePoolID, nodePool.Status.Type)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
+
+// WaitForNodePoolDeleted polls until GETs for the node pool return an error
+// (the provision API 404s deleted pools) or the timeout elapses.
+func (c *Client) WaitForNodePoolDeleted(clusterID, nodePoolID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		_, err := c.GetNodePool(clusterID, nodePoolID)
+		if err != nil {
+			// The pool is gone. Like WaitForClusterDeleted, this conflates
+			// other API errors with deletion, but a stuck delete will still
+			// trip the timeout on retry in practice.
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for node pool %s to be deleted", nodePoolID)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
+
+// WaitForClusterErrorState polls the cluster until it reaches the error
+// state, returning the status it surfaced. It errors if the cluster reaches
+// running instead or the timeout elapses, for chaos tests that expect a
+// provision to fail loudly rather than hang.
+func (c *Client) WaitForClusterErrorState(clusterID string, timeout time.Duration) (*Status, error) {
+	deadline := time.Now().Add(timeout)
+	what := fmt.Sprintf("cluster-%s", clusterID)
+	stuck := newStuckDetector(c, clusterID, what)
+	states := newStateTracker(c, clusterID, what,
+		ClusterStatusProvisioning, ClusterStatusDeleting)
+
+	for {
+		cluster, err := c.GetCluster(clusterID)
+		if err != nil {
+			return nil, err
+		}
+		stuck.observe(cluster.Status)
+
+		switch cluster.Status.Type {
+		case ClusterStatusError:
+			return &cluster.Status, nil
+		case ClusterStatusRunning:
+			return nil, errors.Errorf("cluster %s unexpectedly reached running state", clusterID)
+		default:
+			if err := states.check(cluster.Status); err != nil {
+				return nil, err
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("cluster %s never surfaced an error state within %s (last status: %s)",
+				clusterID, timeout, cluster.Status.Type)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
+
+// PoolTeardown records when a node pool's nodes disappeared from the cloud
+// API during cluster deletion.
+type PoolTeardown struct {
+	NodePoolID string `json:"node_pool_id"`
+	Name       string `json:"name,omitempty"`
+	// NodesAtStart is how many nodes the pool had when teardown began.
+	NodesAtStart int `json:"nodes_at_start"`
+	// Cleared indicates the pool's nodes all disappeared before the timeout.
+	Cleared bool `json:"cleared"`
+	// ClearedAfterSeconds is how long after the DELETE the pool emptied.
+	ClearedAfterSeconds int `json:"cleared_after_seconds,omitempty"`
+}
+
+// TeardownReport localizes where time went (or where things got stuck)
+// during cluster deletion.
+type TeardownReport struct {
+	// DeletingAfterSeconds is how long until the cluster reported deleting.
+	DeletingAfterSeconds int `json:"deleting_after_seconds"`
+	// GoneAfterSeconds is how long until the cluster disappeared entirely.
+	GoneAfterSeconds int            `json:"gone_after_seconds,omitempty"`
+	Pools            []PoolTeardown `json:"pools"`
+}
+
+// WaitForClusterTeardown waits for a deleted cluster to disappear like
+// WaitForClusterDeleted, but additionally tracks each node pool's nodes
+// draining from the cloud API in parallel with the DELETING state. The
+// report is returned even on timeout so a stuck teardown can be localized
+// to the pool that never emptied.
+func (c *Client) WaitForClusterTeardown(clusterID string, timeout time.Duration) (*TeardownReport, error) {
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	report := &TeardownReport{}
+	if nodePools, err := c.ListNodePools(clusterID); err == nil {
+		for _, nodePool := range nodePools {
+			pool := PoolTeardown{
+				NodePoolID: nodePool.ID,
+				Name:       nodePool.Name,
+			}
+			if nodes, err := c.ListNodes(clusterID, nodePool.ID); err == nil {
+				pool.NodesAtStart = len(nodes)
+			}
+			report.Pools = append(report.Pools, pool)
+		}
+	}
+
+	for {
+		cluster, err := c.GetCluster(clusterID)
+		if err != nil {
+			// The cluster is gone; any pool we never saw empty went down
+			// with it.
+			report.GoneAfterSeconds = int(time.Since(start).Seconds())
+			for i := range report.Pools {
+				if !report.Pools[i].Cleared {
+					report.Pools[i].Cleared = true
+					report.Pools[i].ClearedAfterSeconds = report.GoneAfterSeconds
+				}
+			}
+			return report, nil
+		}
+
+		if report.DeletingAfterSeconds == 0 && cluster.Status.Type == ClusterStatusDeleting {
+			report.DeletingAfterSeconds = int(time.Since(start).Seconds())
+		}
+
+		for i := range report.Pools {
+			if report.Pools[i].Cleared {
+				continue
+			}
+			nodes, err := c.ListNodes(clusterID, report.Pools[i].NodePoolID)
+			if err != nil || len(nodes) == 0 {
+				report.Pools[i].Cleared = true
+				report.Pools[i].ClearedAfterSeconds = int(time.Since(start).Seconds())
+			}
+		}
+
+		if time.Now().After(deadline) {
+			var stuckPools []string
+			for _, pool := range report.Pools {
+				if !pool.Cleared {
+					stuckPools = append(stuckPools, pool.NodePoolID)
+				}
+			}
+			return report, errors.Errorf("timed out waiting for cluster %s teardown (pools with remaining nodes: %v)",
+				clusterID, stuckPools)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
+
+// ForceDeleteGracePeriod is how long an escalated force-delete gets to
+// finish after a normal delete has already timed out.
+const ForceDeleteGracePeriod = 10 * time.Minute
+
+// EscalateStuckDelete is the escalation path for a delete that exceeded its
+// timeout: it dumps diagnostics while the stuck state is live, attempts a
+// force-delete, and waits ForceDeleteGracePeriod for that to finish. If the
+// cluster still remains it is recorded in a manual-cleanup artifact so the
+// run summary shows it needs human attention, and an error is returned.
+func (c *Client) EscalateStuckDelete(clusterID string) error {
+	//nolint:errcheck - diagnostics are best-effort on an already-failed path
+	c.DumpState(clusterID, "")
+
+	if err := c.ForceDeleteCluster(clusterID); err != nil {
+		//nolint:errcheck
+		artifacts.WriteJSON("manual-cleanup-required.json", map[string]string{
+			"cluster_id": clusterID,
+			"reason":     fmt.Sprintf("delete timed out and force-delete failed: %v", err),
+		})
+		return errors.Wrapf(err, "cluster %s requires manual cleanup", clusterID)
+	}
+
+	if err := c.WaitForClusterDeleted(clusterID, ForceDeleteGracePeriod); err != nil {
+		//nolint:errcheck
+		artifacts.WriteJSON("manual-cleanup-required.json", map[string]string{
+			"cluster_id": clusterID,
+			"reason":     "cluster survived both a normal delete and a force-delete",
+		})
+		return errors.Wrapf(err, "cluster %s requires manual cleanup", clusterID)
+	}
+
+	return nil
+}
+
+// WaitForClusterDeleted polls until GETs for the cluster return an error
+// (the provision API 404s deleted clusters) or the timeout elapses.
+func (c *Client) WaitForClusterDeleted(clusterID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		_, err := c.GetCluster(clusterID)
+		if err != nil {
+			// The cluster is gone. This conflates other API errors with
+			// deletion, but a stuck delete will still trip the timeout on
+			// retry in practice.
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for cluster %s to be deleted", clusterID)
+		}
+
+		time.Sleep(config.PollInterval())
+	}
+}
@@ -0,0 +1,104 @@
+// Package compat pins the wire types an earlier release of this harness
+// (and the CLI/clientset generation shipped alongside it) was built
+// against, so the compat suite can prove the current backend still serves
+// those clients. The types here are deliberately frozen copies: do not
+// sync them with pkg/cloud when the live types grow fields — drifting the
+// pin forward is exactly what would hide a backward-compatibility break.
+package compat
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Status is the pinned status block.
+type Status struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	Percent string `json:"percent,omitempty"`
+}
+
+// Cluster is the pinned cluster shape. It predates the labels and
+// api_server_address fields the live types carry.
+type Cluster struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	OwnerID           string `json:"owner_id,omitempty"`
+	OrganizationID    string `json:"organization_id,omitempty"`
+	TemplateID        string `json:"template_id,omitempty"`
+	ProviderName      string `json:"provider_name,omitempty"`
+	Environment       string `json:"environment,omitempty"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	Status            Status `json:"status"`
+	CreatedAt         string `json:"created_at,omitempty"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
+}
+
+// NodePool is the pinned node pool shape. It predates the labels and
+// taints fields.
+type NodePool struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	ClusterID         string `json:"cluster_id,omitempty"`
+	KubernetesMode    string `json:"kubernetes_mode,omitempty"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	InstanceSize      string `json:"instance_size,omitempty"`
+	Count             int32  `json:"count"`
+	Status            Status `json:"status"`
+	CreatedAt         string `json:"created_at,omitempty"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
+}
+
+// Template is the pinned template shape.
+type Template struct {
+	ID            string          `json:"id"`
+	ProviderName  string          `json:"provider_name"`
+	Description   string          `json:"description,omitempty"`
+	Engine        string          `json:"engine,omitempty"`
+	Configuration json.RawMessage `json:"configuration,omitempty"`
+	CreatedAt     string          `json:"created_at,omitempty"`
+}
+
+// DecodeCluster decodes a raw API response the way the pinned client
+// would (tolerating unknown fields) and verifies the fields that client
+// depended on are still populated.
+func DecodeCluster(raw []byte) (*Cluster, error) {
+	var cluster Cluster
+	if err := json.Unmarshal(raw, &cluster); err != nil {
+		return nil, errors.Wrap(err, "decoding cluster with pinned types")
+	}
+	if cluster.ID == "" || cluster.Name == "" || cluster.Status.Type == "" {
+		return nil, errors.Errorf("pinned cluster fields are no longer populated: id=%q name=%q status=%q",
+			cluster.ID, cluster.Name, cluster.Status.Type)
+	}
+	return &cluster, nil
+}
+
+// DecodeNodePool decodes a raw node pool with the pinned types and
+// verifies the fields the pinned client depended on.
+func DecodeNodePool(raw []byte) (*NodePool, error) {
+	var pool NodePool
+	if err := json.Unmarshal(raw, &pool); err != nil {
+		return nil, errors.Wrap(err, "decoding node pool with pinned types")
+	}
+	if pool.ID == "" || pool.KubernetesMode == "" || pool.Status.Type == "" {
+		return nil, errors.Errorf("pinned node pool fields are no longer populated: id=%q mode=%q status=%q",
+			pool.ID, pool.KubernetesMode, pool.Status.Type)
+	}
+	return &pool, nil
+}
+
+// DecodeTemplate decodes a raw template with the pinned types and
+// verifies the fields the pinned client depended on.
+func DecodeTemplate(raw []byte) (*Template, error) {
+	var template Template
+	if err := json.Unmarshal(raw, &template); err != nil {
+		return nil, errors.Wrap(err, "decoding template with pinned types")
+	}
+	if template.ID == "" || template.ProviderName == "" || len(template.Configuration) == 0 {
+		return nil, errors.Errorf("pinned template fields are no longer populated: id=%q provider=%q",
+			template.ID, template.ProviderName)
+	}
+	return &template, nil
+}
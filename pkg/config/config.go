@@ -0,0 +1,208 @@
+// Package config loads the shared e2e-config.yaml file. CI jobs used to
+// pass a different pile of flags and environment variables per suite; a
+// single file given via --config (or E2E_CONFIG_FILE) now carries the
+// common settings, with flags and environment variables still overriding
+// individual values.
+package config
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+var configPathFlag = flag.String("config", "",
+	"path to the shared e2e config file (overrides E2E_CONFIG_FILE)")
+
+// Flag overrides for the waits below. Zero means unset and defers to the
+// config file, then the built-in default, so a slow provider can be
+// accommodated per invocation instead of by recompiling with bumped
+// constants.
+var (
+	provisionTimeoutFlag = flag.Duration("provision-timeout", 0,
+		"how long to wait for a cluster to reach running (0 defers to the config file, then the built-in default)")
+	defaultTimeoutFlag = flag.Duration("default-timeout", 0,
+		"default wait for cluster-side operations without a more specific timeout (0 defers to the config file, then the built-in default)")
+	pollIntervalFlag = flag.Duration("poll-interval", 0,
+		"interval at which to poll the cloud APIs while waiting (0 defers to the config file, then the built-in default)")
+)
+
+// File mirrors the e2e-config.yaml layout. Every field is optional; a
+// missing field defers to the flag, environment variable, or built-in
+// default that governed it before the file existed.
+type File struct {
+	// Token is the Containership JWT to authenticate with; TokenFile
+	// points at a file holding it instead, for CI systems that mount
+	// secrets as files.
+	Token     string `json:"token,omitempty"`
+	TokenFile string `json:"token_file,omitempty"`
+
+	Environment      string `json:"environment,omitempty"`
+	APIBaseURL       string `json:"api_base_url,omitempty"`
+	AuthBaseURL      string `json:"auth_base_url,omitempty"`
+	ProvisionBaseURL string `json:"provision_base_url,omitempty"`
+	OrganizationID   string `json:"organization_id,omitempty"`
+
+	// Provider selects the built-in template for that provider when
+	// TemplatePath is not set explicitly.
+	Provider          string `json:"provider,omitempty"`
+	TemplatePath      string `json:"template_path,omitempty"`
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	SSHPublicKeyPath  string `json:"ssh_public_key_path,omitempty"`
+
+	Timeouts Timeouts `json:"timeouts,omitempty"`
+
+	// TransitionalStates lists extra cluster and node pool states the wait
+	// helpers should treat as in-flight, for backend releases that
+	// introduce sub-states (e.g. "etcd_provisioning") before the harness
+	// learns about them.
+	TransitionalStates []string `json:"transitional_states,omitempty"`
+}
+
+// Timeouts overrides the built-in waits for long-running cloud operations,
+// in minutes (the poll interval in seconds); zero values keep the
+// constants package defaults.
+type Timeouts struct {
+	ProvisionMinutes int `json:"provision_minutes,omitempty"`
+	DeleteMinutes    int `json:"delete_minutes,omitempty"`
+	ScaleMinutes     int `json:"scale_minutes,omitempty"`
+	DefaultMinutes   int `json:"default_minutes,omitempty"`
+	PollSeconds      int `json:"poll_seconds,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	loaded   File
+	loadErr  error
+)
+
+// Load parses the configured file once and returns it. With no file
+// configured it returns an empty config, so callers can consult fields
+// unconditionally.
+func Load() (*File, error) {
+	loadOnce.Do(func() {
+		path := *configPathFlag
+		if path == "" {
+			path = os.Getenv(constants.ConfigFileEnvVar)
+		}
+		if path == "" {
+			return
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			loadErr = errors.Wrapf(err, "reading config file %s", path)
+			return
+		}
+		if err := yaml.Unmarshal(data, &loaded); err != nil {
+			loadErr = errors.Wrapf(err, "parsing config file %s", path)
+		}
+	})
+
+	return &loaded, loadErr
+}
+
+// Token resolves the Containership token: the environment variable wins,
+// then the file's inline token, then the contents of its token_file.
+func Token() (string, error) {
+	if token := os.Getenv(constants.TokenEnvVar); token != "" {
+		return token, nil
+	}
+
+	file, err := Load()
+	if err != nil {
+		return "", err
+	}
+	if file.Token != "" {
+		return file.Token, nil
+	}
+	if file.TokenFile != "" {
+		data, err := os.ReadFile(file.TokenFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading token file %s", file.TokenFile)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	return "", nil
+}
+
+// ProvisionTimeout returns the configured provision wait: the flag wins,
+// then the config file, then the constants package value.
+func ProvisionTimeout() time.Duration {
+	if *provisionTimeoutFlag > 0 {
+		return *provisionTimeoutFlag
+	}
+	return minutesOr(fileTimeouts().ProvisionMinutes, constants.ProvisionTimeout)
+}
+
+// DefaultTimeout returns the configured default wait for cluster-side
+// operations that don't have a more specific timeout: the flag wins, then
+// the config file, then the constants package value.
+func DefaultTimeout() time.Duration {
+	if *defaultTimeoutFlag > 0 {
+		return *defaultTimeoutFlag
+	}
+	return minutesOr(fileTimeouts().DefaultMinutes, constants.DefaultTimeout)
+}
+
+// PollInterval returns the configured cloud API poll interval: the flag
+// wins, then the config file, then the constants package value.
+func PollInterval() time.Duration {
+	if *pollIntervalFlag > 0 {
+		return *pollIntervalFlag
+	}
+	if seconds := fileTimeouts().PollSeconds; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return constants.DefaultPollInterval
+}
+
+// DeleteTimeout returns the configured delete wait, defaulting to the
+// constants package value.
+func DeleteTimeout() time.Duration {
+	return minutesOr(fileTimeouts().DeleteMinutes, constants.DeleteTimeout)
+}
+
+// ScaleTimeout returns the configured scale wait, defaulting to the
+// constants package value.
+func ScaleTimeout() time.Duration {
+	return minutesOr(fileTimeouts().ScaleMinutes, constants.ScaleTimeout)
+}
+
+// TransitionalStates returns the extra transitional states from the config
+// file, tolerating a missing or broken file for the same reason as
+// fileTimeouts.
+func TransitionalStates() []string {
+	file, err := Load()
+	if err != nil {
+		return nil
+	}
+	return file.TransitionalStates
+}
+
+// fileTimeouts returns the file's timeout block, tolerating a missing or
+// broken file: timeout resolution shouldn't be the place a config error
+// first surfaces, so parse errors are left for Token and the environment
+// package to report.
+func fileTimeouts() Timeouts {
+	file, err := Load()
+	if err != nil {
+		return Timeouts{}
+	}
+	return file.Timeouts
+}
+
+func minutesOr(minutes int, fallback time.Duration) time.Duration {
+	if minutes <= 0 {
+		return fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,124 @@
+// Package constants contains constants shared across the e2e suites.
+package constants
+
+import "time"
+
+// Containership stage environment endpoints, the defaults when no
+// environment is selected (see pkg/environment).
+const (
+	// StageAPIBaseURL is the base URL of the stage Containership Cloud API.
+	StageAPIBaseURL = "https://stage-api.containership.io"
+	// StageAuthBaseURL is the base URL of the stage auth API.
+	StageAuthBaseURL = "https://stage-auth.containership.io"
+	// StageProvisionBaseURL is the base URL of the stage provision API.
+	StageProvisionBaseURL = "https://stage-provision.containership.io"
+)
+
+// Environment variable names used to configure the suites.
+const (
+	// TokenEnvVar is the environment variable holding the Containership
+	// Cloud API token to authenticate with.
+	TokenEnvVar = "CONTAINERSHIP_TOKEN"
+	// OrganizationIDEnvVar is the environment variable holding the ID of
+	// the organization to run tests against.
+	OrganizationIDEnvVar = "CONTAINERSHIP_ORGANIZATION_ID"
+	// ArtifactsEnvVar is the environment variable holding the directory
+	// that test artifacts should be written to.
+	ArtifactsEnvVar = "ARTIFACTS"
+	// ClusterIDEnvVar is the environment variable holding the ID of an
+	// already-provisioned cluster for suites that run after provisioning.
+	ClusterIDEnvVar = "CONTAINERSHIP_CLUSTER_ID"
+	// TemplateIDEnvVar is the environment variable holding the ID of the
+	// template the cluster under test was provisioned from.
+	TemplateIDEnvVar = "CONTAINERSHIP_TEMPLATE_ID"
+	// TemplatePathEnvVar is the environment variable holding the path to
+	// the provision template file to use.
+	TemplatePathEnvVar = "CONTAINERSHIP_TEMPLATE_PATH"
+	// EnvironmentEnvVar is the environment variable selecting the
+	// Containership environment to target (dev, stage, production).
+	EnvironmentEnvVar = "E2E_ENVIRONMENT"
+	// APIBaseURLEnvVar overrides the cloud API base URL.
+	APIBaseURLEnvVar = "E2E_API_BASE_URL"
+	// AuthBaseURLEnvVar overrides the auth API base URL.
+	AuthBaseURLEnvVar = "E2E_AUTH_BASE_URL"
+	// ProvisionBaseURLEnvVar overrides the provision API base URL.
+	ProvisionBaseURLEnvVar = "E2E_PROVISION_BASE_URL"
+	// QuarantineFileEnvVar is the environment variable holding the path
+	// to the quarantine config file for known-issue specs.
+	QuarantineFileEnvVar = "E2E_QUARANTINE_FILE"
+	// MetricsHistoryEnvVar is the environment variable holding the path
+	// to the per-run metrics history file used for regression detection.
+	MetricsHistoryEnvVar = "E2E_METRICS_HISTORY"
+	// SpecHistoryEnvVar is the environment variable holding the path to
+	// the per-spec outcome history file used for flakiness scoring.
+	SpecHistoryEnvVar = "E2E_SPEC_HISTORY"
+	// ProviderOptionsFileEnvVar is the environment variable holding the
+	// path to the provider-specific cluster create options file.
+	ProviderOptionsFileEnvVar = "E2E_PROVIDER_OPTIONS_FILE"
+	// LockFileEnvVar is the environment variable holding the path to the
+	// shared-org lock file used to serialize concurrent CI pipelines.
+	LockFileEnvVar = "E2E_LOCK_FILE"
+	// StateFileEnvVar is the environment variable holding the path to the
+	// pipeline state file that passes cluster and template IDs between
+	// orchestrated suite stages.
+	StateFileEnvVar = "E2E_STATE_FILE"
+	// NodePoolInstanceSizeEnvVar overrides the instance size used by the
+	// node pool lifecycle suite; empty means the provider default.
+	NodePoolInstanceSizeEnvVar = "E2E_NODE_POOL_INSTANCE_SIZE"
+	// NodePoolCountEnvVar overrides the node count used by the node pool
+	// lifecycle suite.
+	NodePoolCountEnvVar = "E2E_NODE_POOL_COUNT"
+	// NodePoolKubernetesVersionEnvVar overrides the Kubernetes version used
+	// by the node pool lifecycle suite; empty means the cluster's version.
+	NodePoolKubernetesVersionEnvVar = "E2E_NODE_POOL_KUBERNETES_VERSION"
+	// ConfigFileEnvVar is the environment variable holding the path to
+	// the shared e2e config file.
+	ConfigFileEnvVar = "E2E_CONFIG_FILE"
+	// OutputDirEnvVar is the environment variable holding the directory
+	// JUnit XML and JSON result files are written to.
+	OutputDirEnvVar = "E2E_OUTPUT_DIR"
+	// LogLevelEnvVar is the environment variable selecting the minimum log
+	// level (debug, info, warn, error) when --log-level is not given.
+	LogLevelEnvVar = "E2E_LOG_LEVEL"
+	// SeverityFileEnvVar is the environment variable holding the path to
+	// the per-check severity config overriding which checks warn vs fail.
+	SeverityFileEnvVar = "E2E_SEVERITY_FILE"
+	// WebhookListenAddrEnvVar is the environment variable holding the local
+	// address the webhook receiver listens on.
+	WebhookListenAddrEnvVar = "E2E_WEBHOOK_LISTEN_ADDR"
+	// WebhookPublicURLEnvVar is the environment variable holding the
+	// publicly reachable URL that routes to the webhook receiver (e.g. an
+	// ngrok tunnel or a CI-provisioned ingress).
+	WebhookPublicURLEnvVar = "E2E_WEBHOOK_PUBLIC_URL"
+)
+
+// DefaultKubernetesVersion is the Kubernetes version templates are
+// rendered with when no --kubernetes-version is given.
+const DefaultKubernetesVersion = "1.15.3"
+
+// Timeouts and poll intervals for long-running cloud operations.
+const (
+	// ProvisionTimeout is how long to wait for a cluster to reach the
+	// running state after creation.
+	ProvisionTimeout = 45 * time.Minute
+	// DeleteTimeout is how long to wait for a cluster to be fully deleted.
+	DeleteTimeout = 20 * time.Minute
+	// ScaleTimeout is how long to wait for a node pool to return to the
+	// running state after a scale operation.
+	ScaleTimeout = 20 * time.Minute
+	// ErrorSurfaceTimeout is how long a deliberately broken provision may
+	// take to surface an error state before we consider it hung.
+	ErrorSurfaceTimeout = 30 * time.Minute
+	// DefaultTimeout is the wait applied to cluster-side operations when a
+	// suite doesn't define a more specific one.
+	DefaultTimeout = 10 * time.Minute
+	// DefaultPollInterval is the default interval at which to poll the
+	// cloud APIs while waiting for a state transition.
+	DefaultPollInterval = 15 * time.Second
+	// TimelinePollInterval is the interval at which the background
+	// timeline poller samples cluster and node pool statuses.
+	TimelinePollInterval = 10 * time.Second
+	// LockAcquireTimeout is how long a run waits to acquire the shared
+	// org lock before giving up.
+	LockAcquireTimeout = 45 * time.Minute
+)
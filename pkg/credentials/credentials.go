@@ -0,0 +1,192 @@
+// Package credentials loads provider SDK credentials from the environment
+// once per process and hands out scoped, pre-authenticated HTTP clients.
+// Suites that talk to provider APIs directly (cleanup verification, leak
+// detection, chaos) get their clients here instead of each re-implementing
+// token acquisition. A provider whose credentials aren't configured yields
+// a nil client with no error, so callers can skip gracefully.
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Environment variables holding Azure service principal credentials.
+const (
+	azureTenantIDEnvVar       = "AZURE_TENANT_ID"
+	azureClientIDEnvVar       = "AZURE_CLIENT_ID"
+	azureClientSecretEnvVar   = "AZURE_CLIENT_SECRET"
+	azureSubscriptionIDEnvVar = "AZURE_SUBSCRIPTION_ID"
+)
+
+// googleProjectEnvVar holds the GCP project e2e clusters are provisioned
+// into. Credentials come from GOOGLE_APPLICATION_CREDENTIALS as usual.
+const googleProjectEnvVar = "GOOGLE_PROJECT"
+
+// Environment variables holding Packet (Equinix Metal) credentials.
+const (
+	packetAuthTokenEnvVar = "PACKET_AUTH_TOKEN"
+	packetProjectIDEnvVar = "PACKET_PROJECT_ID"
+)
+
+const azureManagementBaseURL = "https://management.azure.com"
+
+// headerTransport injects a static auth header into every request so the
+// client it wraps is already scoped to the provider credentials.
+type headerTransport struct {
+	key   string
+	value string
+	base  http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.Header.Set(t.key, t.value)
+	return t.base.RoundTrip(clone)
+}
+
+func authedClient(key, value string) *http.Client {
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &headerTransport{
+			key:   key,
+			value: value,
+			base:  http.DefaultTransport,
+		},
+	}
+}
+
+// loaded is one provider's cached load result.
+type loaded struct {
+	client *http.Client
+	scope  string
+	err    error
+}
+
+var (
+	azureOnce  sync.Once
+	azure      loaded
+	googleOnce sync.Once
+	gcp        loaded
+	packetOnce sync.Once
+	packet     loaded
+)
+
+// Azure returns an HTTP client authenticated as the service principal from
+// the environment, plus the subscription ID it is scoped to. The token is
+// acquired once per process. Returns (nil, "", nil) if Azure credentials
+// aren't configured.
+func Azure() (*http.Client, string, error) {
+	azureOnce.Do(func() {
+		azure.client, azure.scope, azure.err = loadAzure()
+	})
+	return azure.client, azure.scope, azure.err
+}
+
+func loadAzure() (*http.Client, string, error) {
+	tenantID := os.Getenv(azureTenantIDEnvVar)
+	clientID := os.Getenv(azureClientIDEnvVar)
+	clientSecret := os.Getenv(azureClientSecretEnvVar)
+	subscriptionID := os.Getenv(azureSubscriptionIDEnvVar)
+	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+		return nil, "", nil
+	}
+
+	token, err := azureToken(tenantID, clientID, clientSecret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return authedClient("Authorization", "Bearer "+token), subscriptionID, nil
+}
+
+// azureToken obtains a service principal token for the ARM API.
+func azureToken(tenantID, clientID, clientSecret string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"resource":      {azureManagementBaseURL + "/"},
+	}
+
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+	resp, err := httpClient.PostForm(
+		fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/token", tenantID), form)
+	if err != nil {
+		return "", errors.Wrap(err, "requesting Azure token")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "reading Azure token response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Azure token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", errors.Wrap(err, "unmarshaling Azure token response")
+	}
+
+	return token.AccessToken, nil
+}
+
+// Google returns an HTTP client authenticated via application default
+// credentials with read-only compute scope, plus the GCP project to
+// operate in. Returns (nil, "", nil) if GCP credentials aren't configured.
+func Google() (*http.Client, string, error) {
+	googleOnce.Do(func() {
+		gcp.client, gcp.scope, gcp.err = loadGoogle()
+	})
+	return gcp.client, gcp.scope, gcp.err
+}
+
+func loadGoogle() (*http.Client, string, error) {
+	project := os.Getenv(googleProjectEnvVar)
+	if project == "" || os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") == "" {
+		return nil, "", nil
+	}
+
+	ctx := context.Background()
+	source, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/compute.readonly")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "building GCP token source")
+	}
+
+	return oauth2.NewClient(ctx, source), project, nil
+}
+
+// Packet returns an HTTP client authenticated with the Packet API token
+// from the environment, plus the project ID it is scoped to. Returns
+// (nil, "", nil) if Packet credentials aren't configured.
+func Packet() (*http.Client, string, error) {
+	packetOnce.Do(func() {
+		packet.client, packet.scope, packet.err = loadPacket()
+	})
+	return packet.client, packet.scope, packet.err
+}
+
+func loadPacket() (*http.Client, string, error) {
+	token := os.Getenv(packetAuthTokenEnvVar)
+	projectID := os.Getenv(packetProjectIDEnvVar)
+	if token == "" || projectID == "" {
+		return nil, "", nil
+	}
+
+	return authedClient("X-Auth-Token", token), projectID, nil
+}
@@ -0,0 +1,168 @@
+// Package diagnostics dumps everything needed to debug a failed spec: node
+// and pod lists, events, Containership cluster and node pool state, and
+// system pod logs. Suites call Collect from a JustAfterEach hook so a
+// failure mid-provision or mid-scale leaves more behind than ginkgo output.
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// logTailLines bounds how much of each system pod log is captured.
+const logTailLines = int64(500)
+
+// Collect dumps cloud- and Kubernetes-side state for the cluster under
+// test into a timestamped directory below the current artifacts scope.
+// Collection is best-effort: every source is attempted and the first error
+// is returned.
+func Collect() error {
+	base, err := artifacts.Dir()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(base, time.Now().UTC().Format("diagnostics-20060102-150405"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if testcontext.CloudClient() != nil && testcontext.ClusterID() != "" {
+		record(collectCloud(dir))
+	}
+	if testcontext.KubeClient() != nil {
+		record(collectKubernetes(dir, testcontext.KubeClient()))
+	}
+
+	return firstErr
+}
+
+// collectCloud dumps the Containership view of the cluster.
+func collectCloud(dir string) error {
+	client := testcontext.CloudClient()
+
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	cluster, err := client.GetCluster(testcontext.ClusterID())
+	record(err)
+	if err == nil {
+		record(writeJSON(dir, "cluster.json", cluster))
+	}
+
+	nodePools, err := client.ListNodePools(testcontext.ClusterID())
+	record(err)
+	if err == nil {
+		record(writeJSON(dir, "node-pools.json", nodePools))
+	}
+
+	if testcontext.TemplateID() != "" {
+		template, err := client.GetTemplate(testcontext.TemplateID())
+		record(err)
+		if err == nil {
+			record(writeJSON(dir, "template.json", template))
+		}
+	}
+
+	return firstErr
+}
+
+// collectKubernetes dumps the Kubernetes view of the cluster: nodes, pods,
+// events, and system pod logs.
+func collectKubernetes(dir string, kube kubernetes.Interface) error {
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+	record(err)
+	if err == nil {
+		record(writeJSON(dir, "nodes.json", nodes.Items))
+	}
+
+	pods, err := kube.CoreV1().Pods(metav1.NamespaceAll).List(testcontext.Context(), metav1.ListOptions{})
+	record(err)
+	if err == nil {
+		record(writeJSON(dir, "pods.json", pods.Items))
+	}
+
+	events, err := kube.CoreV1().Events(metav1.NamespaceAll).List(testcontext.Context(), metav1.ListOptions{})
+	record(err)
+	if err == nil {
+		record(writeJSON(dir, "events.json", events.Items))
+	}
+
+	record(collectSystemLogs(dir, kube))
+
+	return firstErr
+}
+
+// collectSystemLogs captures the tail of every kube-system container log,
+// which is where kubelet, networking, and control plane problems surface.
+func collectSystemLogs(dir string, kube kubernetes.Interface) error {
+	pods, err := kube.CoreV1().Pods(metav1.NamespaceSystem).List(testcontext.Context(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	logDir := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return err
+	}
+
+	tail := logTailLines
+	var firstErr error
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			data, err := kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container.Name,
+				TailLines: &tail,
+			}).DoRaw(testcontext.Context())
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+
+			name := fmt.Sprintf("%s-%s.log", pod.Name, container.Name)
+			if err := os.WriteFile(filepath.Join(logDir, name), data, 0644); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func writeJSON(dir, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name), data, 0644)
+}
@@ -0,0 +1,104 @@
+// Package environment resolves which Containership environment the suites
+// target. Stage remains the default, but the same binaries can be pointed
+// at dev or production-like environments via --environment (or
+// E2E_ENVIRONMENT), with individual endpoints overridable for environments
+// we don't know about.
+package environment
+
+import (
+	"flag"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+var (
+	environmentFlag = flag.String("environment", "",
+		"Containership environment to target (dev, stage, production; default stage)")
+	apiBaseURLFlag       = flag.String("api-base-url", "", "override the cloud API base URL")
+	authBaseURLFlag      = flag.String("auth-base-url", "", "override the auth API base URL")
+	provisionBaseURLFlag = flag.String("provision-base-url", "", "override the provision API base URL")
+	organizationIDFlag   = flag.String("organization-id", "", "override the organization ID to run against")
+)
+
+// Environment is a set of Containership API endpoints to run against.
+type Environment struct {
+	Name             string
+	APIBaseURL       string
+	AuthBaseURL      string
+	ProvisionBaseURL string
+}
+
+// known maps environment names to their well-known endpoints.
+var known = map[string]Environment{
+	"dev": {
+		Name:             "dev",
+		APIBaseURL:       "https://dev-api.containership.io",
+		AuthBaseURL:      "https://dev-auth.containership.io",
+		ProvisionBaseURL: "https://dev-provision.containership.io",
+	},
+	"stage": {
+		Name:             "stage",
+		APIBaseURL:       constants.StageAPIBaseURL,
+		AuthBaseURL:      constants.StageAuthBaseURL,
+		ProvisionBaseURL: constants.StageProvisionBaseURL,
+	},
+	"production": {
+		Name:             "production",
+		APIBaseURL:       "https://api.containership.io",
+		AuthBaseURL:      "https://auth.containership.io",
+		ProvisionBaseURL: "https://provision.containership.io",
+	},
+}
+
+// Current resolves the target environment from flags, the process
+// environment, and the shared config file, in that order: a named
+// environment provides defaults, and per-endpoint overrides are applied on
+// top. An unnamed environment defaults to stage.
+func Current() (*Environment, error) {
+	file, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name := firstNonEmpty(*environmentFlag, os.Getenv(constants.EnvironmentEnvVar), file.Environment, "stage")
+
+	env, ok := known[name]
+	if !ok {
+		// Unknown environments are allowed as long as every endpoint is
+		// supplied explicitly.
+		env = Environment{Name: name}
+	}
+
+	env.APIBaseURL = firstNonEmpty(*apiBaseURLFlag, os.Getenv(constants.APIBaseURLEnvVar), file.APIBaseURL, env.APIBaseURL)
+	env.AuthBaseURL = firstNonEmpty(*authBaseURLFlag, os.Getenv(constants.AuthBaseURLEnvVar), file.AuthBaseURL, env.AuthBaseURL)
+	env.ProvisionBaseURL = firstNonEmpty(*provisionBaseURLFlag, os.Getenv(constants.ProvisionBaseURLEnvVar), file.ProvisionBaseURL, env.ProvisionBaseURL)
+
+	if env.APIBaseURL == "" || env.AuthBaseURL == "" || env.ProvisionBaseURL == "" {
+		return nil, errors.Errorf(
+			"unknown environment %q: supply --api-base-url, --auth-base-url, and --provision-base-url", name)
+	}
+
+	return &env, nil
+}
+
+// OrganizationID resolves the organization to run against: flag, then
+// environment variable, then the shared config file.
+func OrganizationID() string {
+	// A broken config file is reported by Current; fall through to the
+	// other sources here rather than failing a string lookup.
+	file, _ := config.Load()
+	return firstNonEmpty(*organizationIDFlag, os.Getenv(constants.OrganizationIDEnvVar), file.OrganizationID)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
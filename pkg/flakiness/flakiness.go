@@ -0,0 +1,198 @@
+// Package flakiness persists per-spec pass/fail outcomes across runs and
+// scores how flaky each spec is, so stabilization effort goes to the specs
+// the data says are flaky instead of the ones people remember being flaky.
+package flakiness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// Outcome is one spec execution result in one run.
+type Outcome struct {
+	Timestamp time.Time `json:"timestamp"`
+	Provider  string    `json:"provider,omitempty"`
+	Spec      string    `json:"spec"`
+	Failed    bool      `json:"failed"`
+}
+
+// Score summarizes a spec's trailing history. Flakiness counts outcome
+// flips between consecutive runs: a spec that always fails scores 0 (it's
+// broken, not flaky), while one that alternates scores near 1.
+type Score struct {
+	Spec     string  `json:"spec"`
+	Provider string  `json:"provider,omitempty"`
+	Runs     int     `json:"runs"`
+	FailRate float64 `json:"fail_rate"`
+	Flaky    float64 `json:"flakiness_score"`
+}
+
+// minRunsForScore is how many recorded runs a spec needs before its score
+// is meaningful enough to report.
+const minRunsForScore = 3
+
+var (
+	mu       sync.Mutex
+	provider string
+	outcomes []Outcome
+)
+
+// SetProvider records the provider the run is exercising, keying history
+// entries so specs aren't compared across providers.
+func SetProvider(p string) {
+	mu.Lock()
+	defer mu.Unlock()
+	provider = p
+}
+
+// Record records one spec outcome for the current run.
+func Record(specText string, failed bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	outcomes = append(outcomes, Outcome{
+		Timestamp: time.Now().UTC(),
+		Provider:  provider,
+		Spec:      specText,
+		Failed:    failed,
+	})
+}
+
+// Flush appends this run's outcomes to the history file (if configured)
+// and writes a flakiness report artifact scored over the full history.
+func Flush() error {
+	historyPath := os.Getenv(constants.SpecHistoryEnvVar)
+	if historyPath == "" {
+		return nil
+	}
+
+	mu.Lock()
+	recorded := outcomes
+	outcomes = nil
+	mu.Unlock()
+
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+
+	if err := appendHistory(historyPath, recorded); err != nil {
+		return err
+	}
+
+	scores := scoreHistory(append(history, recorded...))
+	if len(scores) == 0 {
+		return nil
+	}
+	return artifacts.WriteJSON("flakiness-report.json", scores)
+}
+
+// scoreHistory computes per-(spec, provider) scores for specs with enough
+// history, most flaky first.
+func scoreHistory(history []Outcome) []Score {
+	type key struct{ spec, provider string }
+
+	grouped := map[key][]Outcome{}
+	for _, outcome := range history {
+		k := key{outcome.Spec, outcome.Provider}
+		grouped[k] = append(grouped[k], outcome)
+	}
+
+	var scores []Score
+	for k, group := range grouped {
+		if len(group) < minRunsForScore {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Timestamp.Before(group[j].Timestamp)
+		})
+
+		failures := 0
+		flips := 0
+		for i, outcome := range group {
+			if outcome.Failed {
+				failures++
+			}
+			if i > 0 && outcome.Failed != group[i-1].Failed {
+				flips++
+			}
+		}
+
+		scores = append(scores, Score{
+			Spec:     k.spec,
+			Provider: k.provider,
+			Runs:     len(group),
+			FailRate: float64(failures) / float64(len(group)),
+			Flaky:    float64(flips) / float64(len(group)-1),
+		})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Flaky > scores[j].Flaky
+	})
+	return scores
+}
+
+// loadHistory reads the history file, one JSON outcome per line. A missing
+// file yields an empty history.
+func loadHistory(path string) ([]Outcome, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "opening spec history %s", path)
+	}
+	defer f.Close()
+
+	var history []Outcome
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var outcome Outcome
+		if err := json.Unmarshal(scanner.Bytes(), &outcome); err != nil {
+			return nil, errors.Wrapf(err, "unmarshaling spec history %s", path)
+		}
+		history = append(history, outcome)
+	}
+
+	return history, errors.Wrapf(scanner.Err(), "reading spec history %s", path)
+}
+
+func appendHistory(path string, recorded []Outcome) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening spec history %s", path)
+	}
+	defer f.Close()
+
+	for _, outcome := range recorded {
+		data, err := json.Marshal(outcome)
+		if err != nil {
+			return errors.Wrap(err, "marshaling spec outcome")
+		}
+		if _, err := fmt.Fprintf(f, "%s\n", data); err != nil {
+			return errors.Wrapf(err, "appending to spec history %s", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Package integrity audits Containership-owned metadata on Kubernetes
+// objects. Stale finalizers have blocked cluster cleanup before, so the
+// suites run this audit after provisioning and scaling and again right
+// before deletion.
+package integrity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// Domain is the prefix identifying Containership-owned annotations and
+// finalizers.
+const Domain = "containership.io"
+
+// knownFinalizers is the set of finalizers our controllers legitimately
+// place on objects. Anything else under the domain is treated as stale.
+var knownFinalizers = map[string]bool{
+	"containership.io/cluster-cleanup":   true,
+	"containership.io/node-pool-cleanup": true,
+}
+
+// stuckDeletionThreshold is how long an object may sit with a deletion
+// timestamp and Containership finalizers before the audit flags it.
+const stuckDeletionThreshold = 5 * time.Minute
+
+// Problem is a single audit finding.
+type Problem struct {
+	// Kind and Name identify the object, e.g. "namespace/kube-system".
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Detail explains the finding.
+	Detail string `json:"detail"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s/%s: %s", p.Kind, p.Name, p.Detail)
+}
+
+// Audit checks Containership-owned annotations and finalizers on all nodes
+// and namespaces and returns the problems found. An empty slice means the
+// metadata is healthy.
+func Audit(kube kubernetes.Interface) ([]Problem, error) {
+	var problems []Problem
+
+	nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing nodes")
+	}
+	for _, node := range nodes.Items {
+		problems = append(problems, auditObject("node", node.ObjectMeta)...)
+	}
+
+	namespaces, err := kube.CoreV1().Namespaces().List(testcontext.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing namespaces")
+	}
+	for _, namespace := range namespaces.Items {
+		problems = append(problems, auditObject("namespace", namespace.ObjectMeta)...)
+	}
+
+	return problems, nil
+}
+
+// auditObject checks a single object's Containership-owned metadata.
+func auditObject(kind string, meta metav1.ObjectMeta) []Problem {
+	var problems []Problem
+
+	for key, value := range meta.Annotations {
+		if strings.HasPrefix(key, Domain) && value == "" {
+			problems = append(problems, Problem{
+				Kind:   kind,
+				Name:   meta.Name,
+				Detail: fmt.Sprintf("annotation %s has an empty value", key),
+			})
+		}
+	}
+
+	for _, finalizer := range meta.Finalizers {
+		if !strings.HasPrefix(finalizer, Domain) {
+			continue
+		}
+
+		if !knownFinalizers[finalizer] {
+			problems = append(problems, Problem{
+				Kind:   kind,
+				Name:   meta.Name,
+				Detail: fmt.Sprintf("unknown finalizer %s under the %s domain", finalizer, Domain),
+			})
+		}
+
+		// A deletion that has been blocked on our finalizer for a while is
+		// exactly the failure mode that has wedged cleanup before.
+		if meta.DeletionTimestamp != nil && time.Since(meta.DeletionTimestamp.Time) > stuckDeletionThreshold {
+			problems = append(problems, Problem{
+				Kind: kind,
+				Name: meta.Name,
+				Detail: fmt.Sprintf("deletion blocked on finalizer %s since %s",
+					finalizer, meta.DeletionTimestamp.Time.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	return problems
+}
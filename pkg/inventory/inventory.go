@@ -0,0 +1,121 @@
+// Package inventory reconciles the provision API's view of a cluster's
+// nodes against the Kubernetes view, flagging ghosts on either side. Drift
+// between the two inventories means the node sync is broken: either cloud
+// nodes never registered, or Kubernetes nodes outlived their cloud records.
+package inventory
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// Labels the node sync controller stamps on Kubernetes nodes.
+const (
+	// NodePoolIDLabel holds the ID of the node pool the node belongs to.
+	NodePoolIDLabel = "containership.io/node-pool-id"
+	// InstanceIDLabel holds the provider-side instance ID of the node.
+	InstanceIDLabel = "containership.io/instance-id"
+)
+
+// Ghost is a node present in one inventory but not the other.
+type Ghost struct {
+	// InstanceID is the provider-side instance ID, when known.
+	InstanceID string `json:"instance_id,omitempty"`
+	// NodePoolID is the pool the node claims to belong to, when known.
+	NodePoolID string `json:"node_pool_id,omitempty"`
+	// KubernetesName is the Kubernetes node name, for Kubernetes-side ghosts.
+	KubernetesName string `json:"kubernetes_name,omitempty"`
+	// Reason explains why the node is considered a ghost.
+	Reason string `json:"reason"`
+}
+
+// Report is the result of reconciling the two inventories.
+type Report struct {
+	// CloudOnly are nodes the provision API tracks that have no matching
+	// Kubernetes node.
+	CloudOnly []Ghost `json:"cloud_only,omitempty"`
+	// KubernetesOnly are Kubernetes nodes with no matching cloud record,
+	// including nodes whose pool label disagrees with the cloud's view.
+	KubernetesOnly []Ghost `json:"kubernetes_only,omitempty"`
+}
+
+// Empty returns true if the inventories agree.
+func (r *Report) Empty() bool {
+	return len(r.CloudOnly) == 0 && len(r.KubernetesOnly) == 0
+}
+
+// Diff reconciles the provision API's per-pool node lists against the
+// Kubernetes node list for the given cluster.
+func Diff(client *cloud.Client, kube kubernetes.Interface, clusterID string) (*Report, error) {
+	nodePools, err := client.ListNodePools(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cloud view: instance ID -> owning pool ID.
+	cloudInstances := map[string]string{}
+	for _, nodePool := range nodePools {
+		nodes, err := client.ListNodes(clusterID, nodePool.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			cloudInstances[node.InstanceID] = nodePool.ID
+		}
+	}
+
+	kubeNodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing Kubernetes nodes")
+	}
+
+	var report Report
+	seenInstances := map[string]bool{}
+
+	for _, node := range kubeNodes.Items {
+		instanceID := node.Labels[InstanceIDLabel]
+		poolID := node.Labels[NodePoolIDLabel]
+
+		cloudPoolID, known := cloudInstances[instanceID]
+		switch {
+		case instanceID == "":
+			report.KubernetesOnly = append(report.KubernetesOnly, Ghost{
+				KubernetesName: node.Name,
+				NodePoolID:     poolID,
+				Reason:         "node has no instance ID label",
+			})
+		case !known:
+			report.KubernetesOnly = append(report.KubernetesOnly, Ghost{
+				KubernetesName: node.Name,
+				InstanceID:     instanceID,
+				NodePoolID:     poolID,
+				Reason:         "instance is not tracked by the provision API",
+			})
+		case cloudPoolID != poolID:
+			report.KubernetesOnly = append(report.KubernetesOnly, Ghost{
+				KubernetesName: node.Name,
+				InstanceID:     instanceID,
+				NodePoolID:     poolID,
+				Reason:         "node pool label disagrees with the provision API (cloud says " + cloudPoolID + ")",
+			})
+		}
+
+		seenInstances[instanceID] = true
+	}
+
+	for instanceID, poolID := range cloudInstances {
+		if !seenInstances[instanceID] {
+			report.CloudOnly = append(report.CloudOnly, Ghost{
+				InstanceID: instanceID,
+				NodePoolID: poolID,
+				Reason:     "cloud node never registered with Kubernetes",
+			})
+		}
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,80 @@
+// Package k8s builds Kubernetes clients for clusters under test. Access
+// goes through the Containership API's Kubernetes proxy by default, so no
+// provider-side credentials or kubeconfig juggling is needed; an explicit
+// KUBECONFIG wins when set (e.g. for local debugging).
+package k8s
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/environment"
+)
+
+// New returns a clientset for the given cluster.
+func New(clusterID string) (kubernetes.Interface, error) {
+	config, err := restConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "building Kubernetes clientset")
+	}
+
+	return clientset, nil
+}
+
+// Config returns the rest config for the given cluster, for callers that
+// need more than a clientset (exec, port-forward, custom streams).
+func Config(clusterID string) (*rest.Config, error) {
+	return restConfig(clusterID)
+}
+
+// restConfig builds the rest config for the cluster, from KUBECONFIG when
+// set and the Containership Kubernetes proxy otherwise.
+func restConfig(clusterID string) (*rest.Config, error) {
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		return config, errors.Wrapf(err, "loading kubeconfig %s", kubeconfig)
+	}
+
+	token, err := config.Token()
+	if err != nil {
+		return nil, err
+	}
+	organizationID := environment.OrganizationID()
+	if token == "" || organizationID == "" {
+		return nil, errors.Errorf("%s and %s must be set to use the Kubernetes proxy",
+			constants.TokenEnvVar, constants.OrganizationIDEnvVar)
+	}
+
+	env, err := environment.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	proxyConfig := &rest.Config{
+		Host: fmt.Sprintf("%s/v3/organizations/%s/clusters/%s/k8sapi/proxy",
+			env.APIBaseURL, organizationID, clusterID),
+		BearerToken: token,
+	}
+
+	// The direct-fallback config is deliberately not instrumented: the
+	// stats measure the proxy, and a degraded run already writes its own
+	// artifact.
+	instrument(proxyConfig)
+
+	if *directFallback {
+		return withDirectFallback(clusterID, proxyConfig)
+	}
+	return proxyConfig, nil
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/log"
+)
+
+// directFallback enables falling back to the cluster's direct API endpoint
+// when the Containership Kubernetes proxy is unreachable. Off by default:
+// the proxy is part of the product under test, so a run should normally
+// fail loudly when it breaks rather than route around it.
+var directFallback = flag.Bool("direct-fallback", false,
+	"fall back to the cluster's direct API endpoint when the Kubernetes proxy is unreachable")
+
+// proxyProbeTimeout bounds the reachability probe; the proxy answers
+// /version in well under a second when healthy.
+const proxyProbeTimeout = 15 * time.Second
+
+// withDirectFallback returns proxyConfig if the proxy answers a probe, and
+// otherwise builds a config against the cluster's direct API endpoint from
+// credentials fetched through the provision API. The fallback is recorded
+// to an artifact so the run attributes the degradation to the proxy rather
+// than the cluster.
+func withDirectFallback(clusterID string, proxyConfig *rest.Config) (*rest.Config, error) {
+	probeErr := probeProxy(proxyConfig)
+	if probeErr == nil {
+		return proxyConfig, nil
+	}
+
+	log.Warnf("Kubernetes proxy for cluster %s is unreachable (%v); falling back to the direct endpoint",
+		clusterID, probeErr)
+
+	client, err := cloud.New()
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := client.GetClusterCredentials(clusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "proxy is unreachable and direct credentials could not be fetched")
+	}
+	if credentials.APIServerAddress == "" || credentials.Token == "" {
+		return nil, errors.Errorf(
+			"proxy is unreachable and cluster %s exposes no direct credentials: %v", clusterID, probeErr)
+	}
+
+	//nolint:errcheck - attribution is best-effort; the fallback config matters more
+	artifacts.WriteJSON("proxy-degraded.json", map[string]string{
+		"cluster_id":  clusterID,
+		"probe_error": probeErr.Error(),
+		"fallback":    credentials.APIServerAddress,
+	})
+
+	return &rest.Config{
+		Host:        credentials.APIServerAddress,
+		BearerToken: credentials.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: []byte(credentials.CACertificate),
+		},
+	}, nil
+}
+
+// probeProxy checks that the proxy answers a version request at all. Any
+// HTTP response counts as reachable — an apiserver-side error should fail
+// specs as itself, not trigger the fallback.
+func probeProxy(proxyConfig *rest.Config) error {
+	req, err := http.NewRequest(http.MethodGet, proxyConfig.Host+"/version", nil)
+	if err != nil {
+		return errors.Wrap(err, "building proxy probe request")
+	}
+	req.Header.Set("Authorization", "Bearer "+proxyConfig.BearerToken)
+
+	httpClient := &http.Client{Timeout: proxyProbeTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "probing the Kubernetes proxy")
+	}
+	resp.Body.Close()
+
+	return nil
+}
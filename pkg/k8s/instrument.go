@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/metrics"
+)
+
+// Every suite talks to the cluster through the Kubernetes proxy, so
+// instrumenting the rest config gives continuous proxy latency and error
+// measurements as a side effect of every run, without the specs doing
+// anything. The stats aggregate per verb and resource (e.g. "GET pods",
+// "POST pods/exec") and land next to the cloud API stats at flush time.
+var (
+	apiStatsMu sync.Mutex
+	apiStats   = map[string]*cloud.EndpointStats{}
+)
+
+func init() {
+	metrics.OnFlush(writeAPIStatsArtifact)
+}
+
+// instrument wraps the config's transport to record every round trip.
+func instrument(config *rest.Config) {
+	config.Wrap(func(next http.RoundTripper) http.RoundTripper {
+		return &instrumentedTransport{next: next}
+	})
+}
+
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	// 4xx responses are not counted as errors: the suites routinely probe
+	// for absence (IsNotFound polls, IsAlreadyExists creates) and those
+	// would drown out real proxy failures.
+	failed := err != nil || resp.StatusCode >= 500
+	observeAPICall(req.Method, req.URL.Path, elapsed, failed)
+
+	return resp, err
+}
+
+// observeAPICall records one API server round trip against its verb and
+// resource.
+func observeAPICall(method, path string, elapsed time.Duration, failed bool) {
+	key := method + " " + resourceForPath(path)
+
+	apiStatsMu.Lock()
+	defer apiStatsMu.Unlock()
+
+	s := apiStats[key]
+	if s == nil {
+		s = &cloud.EndpointStats{}
+		apiStats[key] = s
+	}
+
+	s.Calls++
+	if failed {
+		s.Errors++
+	}
+	seconds := elapsed.Seconds()
+	s.TotalSeconds += seconds
+	if seconds > s.MaxSeconds {
+		s.MaxSeconds = seconds
+	}
+}
+
+// resourceForPath reduces an API server request path to its resource (with
+// subresource if any), dropping names and namespaces so calls against
+// different objects aggregate together.
+func resourceForPath(path string) string {
+	// Strip the proxy prefix down to the API server path proper.
+	if i := strings.Index(path, "/k8sapi/proxy"); i >= 0 {
+		path = path[i+len("/k8sapi/proxy"):]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// /api/v1/<rest> for the core group, /apis/<group>/<version>/<rest>
+	// for everything else.
+	var rest []string
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		rest = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		rest = segments[3:]
+	default:
+		// Discovery, /version, /healthz, ...
+		return "/" + strings.Join(segments, "/")
+	}
+
+	if len(rest) >= 2 && rest[0] == "namespaces" {
+		rest = rest[2:]
+	}
+	if len(rest) == 0 {
+		// A namespaced path with nothing after the namespace is an
+		// operation on the namespace object itself.
+		return "namespaces"
+	}
+
+	resource := rest[0]
+	if len(rest) >= 3 {
+		resource += "/" + rest[2]
+	}
+	return resource
+}
+
+// writeAPIStatsArtifact derives the mean and error rate per verb/resource
+// and writes the snapshot as an artifact next to the run metrics.
+func writeAPIStatsArtifact() {
+	apiStatsMu.Lock()
+	snapshot := make(map[string]cloud.EndpointStats, len(apiStats))
+	for key, s := range apiStats {
+		derived := *s
+		derived.MeanSeconds = derived.TotalSeconds / float64(derived.Calls)
+		derived.ErrorRate = float64(derived.Errors) / float64(derived.Calls)
+		snapshot[key] = derived
+	}
+	apiStatsMu.Unlock()
+
+	if len(snapshot) == 0 {
+		return
+	}
+
+	//nolint:errcheck - stats export must not mask the metrics flush result
+	artifacts.WriteJSON("k8s-api-stats.json", snapshot)
+}
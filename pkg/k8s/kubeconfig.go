@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Kubeconfig renders a kubeconfig that routes through the Containership
+// Kubernetes proxy, for tools that can't take a rest config directly
+// (kubectl, helm). The same KUBECONFIG override as New applies.
+func Kubeconfig(clusterID string) ([]byte, error) {
+	config, err := restConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	const name = "e2e"
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[name] = &clientcmdapi.Cluster{
+		Server: config.Host,
+	}
+	kubeconfig.AuthInfos[name] = &clientcmdapi.AuthInfo{
+		Token: config.BearerToken,
+	}
+	kubeconfig.Contexts[name] = &clientcmdapi.Context{
+		Cluster:  name,
+		AuthInfo: name,
+	}
+	kubeconfig.CurrentContext = name
+
+	data, err := clientcmd.Write(*kubeconfig)
+	return data, errors.Wrap(err, "serializing kubeconfig")
+}
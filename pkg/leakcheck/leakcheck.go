@@ -0,0 +1,78 @@
+// Package leakcheck keeps the harness itself healthy on long matrix/soak
+// runs: it verifies that suites don't leak goroutines and periodically logs
+// memory statistics so runner OOMs can be traced back to the leak.
+package leakcheck
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// VerifyNone asserts that no unexpected goroutines are left running. Call
+// it after RunSpecs returns so suite teardown (which stops background
+// pollers and port-forwards) has already happened.
+func VerifyNone(t *testing.T) {
+	goleak.VerifyNone(t,
+		// The HTTP clients keep idle connections alive briefly.
+		goleak.IgnoreTopFunction("net/http.(*persistConn).readLoop"),
+		goleak.IgnoreTopFunction("net/http.(*persistConn).writeLoop"),
+		goleak.IgnoreTopFunction("internal/poll.runtime_pollWait"),
+	)
+}
+
+// MemStatsLogger periodically logs runtime memory and goroutine statistics.
+type MemStatsLogger struct {
+	out      io.Writer
+	interval time.Duration
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// StartMemStatsLogger starts logging memory statistics to out at the given
+// interval until Stop is called.
+func StartMemStatsLogger(out io.Writer, interval time.Duration) *MemStatsLogger {
+	l := &MemStatsLogger{
+		out:      out,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(l.doneCh)
+
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.stopCh:
+				return
+			case <-ticker.C:
+				l.log()
+			}
+		}
+	}()
+
+	return l
+}
+
+// Stop stops the logger and blocks until its goroutine exits.
+func (l *MemStatsLogger) Stop() {
+	close(l.stopCh)
+	<-l.doneCh
+}
+
+func (l *MemStatsLogger) log() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	fmt.Fprintf(l.out, "[memstats] %s alloc=%dMiB sys=%dMiB num_gc=%d goroutines=%d\n",
+		time.Now().UTC().Format(time.RFC3339),
+		m.Alloc/1024/1024, m.Sys/1024/1024, m.NumGC, runtime.NumGoroutine())
+}
@@ -0,0 +1,166 @@
+// Package lock provides a lightweight lease over the shared test
+// organization so concurrent CI pipelines (and the sweeper) don't trip
+// over each other. The lease is a JSON lock file at a shared path — in
+// practice a bucket-mounted directory — created exclusively by the holder
+// and stolen only once expired.
+package lock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// DefaultTTL bounds how long a crashed run can hold the org before other
+// runs steal the lease.
+const DefaultTTL = 3 * time.Hour
+
+// acquirePollInterval is how often Acquire re-checks a held lease.
+const acquirePollInterval = 30 * time.Second
+
+// Lease is the on-disk representation of a held lock.
+type Lease struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Lock is a lease-based lock at a shared file path.
+type Lock struct {
+	path   string
+	holder string
+	ttl    time.Duration
+}
+
+// New constructs a Lock held by the named holder.
+func New(path, holder string, ttl time.Duration) *Lock {
+	return &Lock{
+		path:   path,
+		holder: holder,
+		ttl:    ttl,
+	}
+}
+
+// FromEnv builds a lock from the environment, returning nil if no lock
+// file is configured (single-pipeline setups need none). The holder is the
+// CI build URL when available so a held lock is traceable to a run.
+func FromEnv() *Lock {
+	path := os.Getenv(constants.LockFileEnvVar)
+	if path == "" {
+		return nil
+	}
+
+	holder := os.Getenv("BUILD_URL")
+	if holder == "" {
+		hostname, _ := os.Hostname()
+		holder = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	return New(path, holder, DefaultTTL)
+}
+
+// Acquire takes the lease, polling until it's free (or expired, in which
+// case it's stolen) or the timeout elapses.
+func (l *Lock) Acquire(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		acquired, err := l.tryAcquire()
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			current, _ := l.read()
+			if current != nil {
+				return errors.Errorf("timed out acquiring lock %s (held by %s until %s)",
+					l.path, current.Holder, current.ExpiresAt.Format(time.RFC3339))
+			}
+			return errors.Errorf("timed out acquiring lock %s", l.path)
+		}
+
+		time.Sleep(acquirePollInterval)
+	}
+}
+
+// Release drops the lease if this lock still holds it.
+func (l *Lock) Release() error {
+	current, err := l.read()
+	if err != nil || current == nil {
+		return err
+	}
+	if current.Holder != l.holder {
+		// Someone stole an expired lease out from under us; their lock is
+		// not ours to remove.
+		return nil
+	}
+
+	return errors.Wrapf(os.Remove(l.path), "removing lock file %s", l.path)
+}
+
+func (l *Lock) tryAcquire() (bool, error) {
+	current, err := l.read()
+	if err != nil {
+		return false, err
+	}
+	if current != nil {
+		if time.Now().Before(current.ExpiresAt) {
+			return false, nil
+		}
+		// Expired lease: steal it.
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrapf(err, "removing expired lock file %s", l.path)
+		}
+	}
+
+	now := time.Now().UTC()
+	data, err := json.Marshal(Lease{
+		Holder:     l.holder,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(l.ttl),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "marshaling lease")
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			// Lost the race to another run.
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "creating lock file %s", l.path)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return false, errors.Wrapf(err, "writing lock file %s", l.path)
+	}
+
+	return true, nil
+}
+
+func (l *Lock) read() (*Lease, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading lock file %s", l.path)
+	}
+
+	var lease Lease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling lock file %s", l.path)
+	}
+
+	return &lease, nil
+}
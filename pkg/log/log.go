@@ -0,0 +1,231 @@
+// Package log is the shared structured logger for the e2e suites and cmd
+// tools. Entries are logfmt-style lines carrying the suite, spec, and
+// cluster ID in flight plus the elapsed run time, so a line in a 40-minute
+// interleaved run can be attributed without scrolling for context:
+//
+//	12:04:31 INFO msg="creating node pool" suite=nodepool cluster=abc123 elapsed=3m10s
+//
+// Entries are written both to the console writer (GinkgoWriter for suites,
+// stdout for cmd tools) and to a per-run log file under the artifacts
+// directory, so the full history survives ginkgo's per-spec buffering. The
+// minimum level is configured with --log-level or E2E_LOG_LEVEL; debug
+// enables HTTP traces from the cloud client.
+package log
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// logLevel overrides the minimum level to emit. The environment variable
+// applies when the flag is unset, and info is the default.
+var logLevel = flag.String("log-level", "",
+	fmt.Sprintf("minimum log level (debug, info, warn, error); %s applies when unset", constants.LogLevelEnvVar))
+
+// Level is the severity of a log entry.
+type Level int
+
+// Levels, in increasing severity.
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+// String returns the level name as it appears in log entries.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARN"
+	case ErrorLevel:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name, case-insensitively.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// start anchors the elapsed field; process start is close enough to run
+// start for attribution purposes.
+var start = time.Now()
+
+// Scope state shared with background goroutines (collectors, pollers, the
+// cloud client), guarded like the artifacts scope.
+var (
+	mu        sync.Mutex
+	console   io.Writer = os.Stdout
+	file      *os.File
+	fileTried bool
+	suiteName string
+	specName  string
+	clusterID string
+)
+
+// SetConsole redirects console output, e.g. to GinkgoWriter so suite log
+// lines interleave correctly with ginkgo's own output. The per-run file
+// sink is unaffected.
+func SetConsole(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	console = w
+}
+
+// SetSuite records the suite currently running. Call it once from the
+// suite entry point, alongside artifacts.SetSuite.
+func SetSuite(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	suiteName = name
+}
+
+// SetSpec records the spec currently running; clear with an empty string.
+func SetSpec(fullText string) {
+	mu.Lock()
+	defer mu.Unlock()
+	specName = fullText
+}
+
+// SetClusterID records the cluster under test so entries can be attributed
+// to it. Wired up by testcontext.SetClusterID; suites don't call it
+// directly.
+func SetClusterID(id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	clusterID = id
+}
+
+// minLevel resolves the configured minimum level: flag, then environment
+// variable, then info. Unparseable values fall back to info rather than
+// failing a run over a typo.
+func minLevel() Level {
+	spec := *logLevel
+	if spec == "" {
+		spec = os.Getenv(constants.LogLevelEnvVar)
+	}
+	if spec == "" {
+		return InfoLevel
+	}
+
+	level, err := ParseLevel(spec)
+	if err != nil {
+		return InfoLevel
+	}
+	return level
+}
+
+// Debugf logs at debug level. Debug entries include HTTP traces from the
+// cloud client, so expect volume.
+func Debugf(format string, args ...interface{}) {
+	emit(DebugLevel, false, format, args...)
+}
+
+// Infof logs at info level.
+func Infof(format string, args ...interface{}) {
+	emit(InfoLevel, false, format, args...)
+}
+
+// Warnf logs at warn level.
+func Warnf(format string, args ...interface{}) {
+	emit(WarnLevel, false, format, args...)
+}
+
+// Errorf logs at error level.
+func Errorf(format string, args ...interface{}) {
+	emit(ErrorLevel, false, format, args...)
+}
+
+// Step logs a spec step at info level, marked step=true. It replaces
+// ginkgo's By so steps land in the structured run log with the same scope
+// fields as everything else.
+func Step(format string, args ...interface{}) {
+	emit(InfoLevel, true, format, args...)
+}
+
+// emit formats one entry and writes it to the console and the per-run
+// file. Both writes are best-effort: logging must never fail a spec.
+func emit(level Level, step bool, format string, args ...interface{}) {
+	if level < minLevel() {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var fields strings.Builder
+	fmt.Fprintf(&fields, " msg=%q", msg)
+	if step {
+		fields.WriteString(" step=true")
+	}
+	if suiteName != "" {
+		fmt.Fprintf(&fields, " suite=%s", suiteName)
+	}
+	if specName != "" {
+		fmt.Fprintf(&fields, " spec=%q", specName)
+	}
+	if clusterID != "" {
+		fmt.Fprintf(&fields, " cluster=%s", clusterID)
+	}
+	fmt.Fprintf(&fields, " elapsed=%s", time.Since(start).Truncate(time.Second))
+
+	line := fmt.Sprintf("%s %-5s%s\n", time.Now().Format("15:04:05"), level, fields.String())
+
+	//nolint:errcheck - logging must not fail the run
+	io.WriteString(console, line)
+	if f := runFile(); f != nil {
+		//nolint:errcheck
+		io.WriteString(f, line)
+	}
+}
+
+// runFile lazily opens the per-run log file under the run-scoped artifacts
+// directory. Opening is attempted once; on failure the logger stays
+// console-only for the rest of the run. Callers hold mu.
+func runFile() *os.File {
+	if fileTried {
+		return file
+	}
+	fileTried = true
+
+	dir, err := artifacts.RunDir()
+	if err != nil {
+		return nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "run.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil
+	}
+	file = f
+	return file
+}
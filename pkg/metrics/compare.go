@@ -0,0 +1,78 @@
+package metrics
+
+import "math"
+
+// trailingRuns is how many of the most recent matching runs to compare
+// against.
+const trailingRuns = 10
+
+// minSamples is the minimum number of historical samples required before a
+// regression can be flagged; below this the variance estimate is useless.
+const minSamples = 3
+
+// Regression flags a metric that is significantly slower in the current
+// run than in the trailing history.
+type Regression struct {
+	Metric  string  `json:"metric"`
+	Value   float64 `json:"value"`
+	Mean    float64 `json:"mean"`
+	StdDev  float64 `json:"std_dev"`
+	Samples int     `json:"samples"`
+}
+
+// detectRegressions compares the current run's metrics against the trailing
+// N runs for the same provider and Kubernetes version. A metric is flagged
+// when it exceeds the historical mean by more than two standard deviations
+// and by at least 10%, which filters normal cloud-side noise.
+func detectRegressions(history []Run, current Run, trailingN int) []Regression {
+	var matching []Run
+	for _, run := range history {
+		if run.Provider == current.Provider && run.KubernetesVersion == current.KubernetesVersion {
+			matching = append(matching, run)
+		}
+	}
+	if len(matching) > trailingN {
+		matching = matching[len(matching)-trailingN:]
+	}
+
+	var regressions []Regression
+	for name, value := range current.Metrics {
+		var samples []float64
+		for _, run := range matching {
+			if v, ok := run.Metrics[name]; ok {
+				samples = append(samples, v)
+			}
+		}
+		if len(samples) < minSamples {
+			continue
+		}
+
+		mean, stdDev := meanStdDev(samples)
+		if value > mean+2*stdDev && value > mean*1.1 {
+			regressions = append(regressions, Regression{
+				Metric:  name,
+				Value:   value,
+				Mean:    mean,
+				StdDev:  stdDev,
+				Samples: len(samples),
+			})
+		}
+	}
+
+	return regressions
+}
+
+func meanStdDev(samples []float64) (float64, float64) {
+	var sum float64
+	for _, s := range samples {
+		sum += s
+	}
+	mean := sum / float64(len(samples))
+
+	var sumSquares float64
+	for _, s := range samples {
+		sumSquares += (s - mean) * (s - mean)
+	}
+
+	return mean, math.Sqrt(sumSquares / float64(len(samples)))
+}
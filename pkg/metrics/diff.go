@@ -0,0 +1,84 @@
+package metrics
+
+import "time"
+
+// slowerThresholdPercent is how much slower a phase must be than the
+// previous run before the diff calls it out. Single-run comparisons are
+// noisier than the trailing-window regression check, so the bar is higher.
+const slowerThresholdPercent = 20.0
+
+// RunDiff summarizes how the current run differs from the previous run of
+// the same provider/version cell: failures that are new, failures that
+// cleared, and phases that got markedly slower. Nightly triage starts here
+// instead of eyeballing two raw metric dumps.
+type RunDiff struct {
+	PreviousTimestamp time.Time `json:"previous_timestamp"`
+
+	NewFailureCategories     []string `json:"new_failure_categories,omitempty"`
+	ClearedFailureCategories []string `json:"cleared_failure_categories,omitempty"`
+
+	SlowerPhases []PhaseDelta `json:"slower_phases,omitempty"`
+}
+
+// PhaseDelta is one timing metric's change versus the previous run.
+type PhaseDelta struct {
+	Metric          string  `json:"metric"`
+	PreviousSeconds float64 `json:"previous_seconds"`
+	CurrentSeconds  float64 `json:"current_seconds"`
+	DeltaPercent    float64 `json:"delta_percent"`
+}
+
+// diffAgainstPrevious compares the current run against the most recent
+// historical run for the same provider and Kubernetes version, returning
+// nil if there is no such run or nothing noteworthy changed.
+func diffAgainstPrevious(history []Run, current Run) *RunDiff {
+	var previous *Run
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Provider == current.Provider &&
+			history[i].KubernetesVersion == current.KubernetesVersion {
+			previous = &history[i]
+			break
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+
+	diff := RunDiff{PreviousTimestamp: previous.Timestamp}
+
+	for category := range current.FailureCategories {
+		if previous.FailureCategories[category] == 0 {
+			diff.NewFailureCategories = append(diff.NewFailureCategories, category)
+		}
+	}
+	for category := range previous.FailureCategories {
+		if current.FailureCategories[category] == 0 {
+			diff.ClearedFailureCategories = append(diff.ClearedFailureCategories, category)
+		}
+	}
+
+	for name, value := range current.Metrics {
+		previousValue, ok := previous.Metrics[name]
+		if !ok || previousValue <= 0 {
+			continue
+		}
+
+		deltaPercent := (value - previousValue) / previousValue * 100
+		if deltaPercent >= slowerThresholdPercent {
+			diff.SlowerPhases = append(diff.SlowerPhases, PhaseDelta{
+				Metric:          name,
+				PreviousSeconds: previousValue,
+				CurrentSeconds:  value,
+				DeltaPercent:    deltaPercent,
+			})
+		}
+	}
+
+	if len(diff.NewFailureCategories) == 0 &&
+		len(diff.ClearedFailureCategories) == 0 &&
+		len(diff.SlowerPhases) == 0 {
+		return nil
+	}
+
+	return &diff
+}
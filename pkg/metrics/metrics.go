@@ -0,0 +1,175 @@
+// Package metrics records per-run timing metrics (provision time,
+// node-ready time, scale time, ...), persists them to a small file-backed
+// history, and flags statistically significant regressions against the
+// trailing runs for the same provider and Kubernetes version.
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// Run is the set of metrics collected during a single run, keyed by metric
+// name with values in seconds.
+type Run struct {
+	Timestamp         time.Time          `json:"timestamp"`
+	Provider          string             `json:"provider"`
+	KubernetesVersion string             `json:"kubernetes_version"`
+	Metrics           map[string]float64 `json:"metrics"`
+	// FailureCategories counts spec failures by classification code, so
+	// triage dashboards can aggregate what kills runs.
+	FailureCategories map[string]int `json:"failure_categories,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	current = Run{
+		Metrics:           map[string]float64{},
+		FailureCategories: map[string]int{},
+	}
+)
+
+// SetRunInfo sets the provider and Kubernetes version the current run is
+// exercising; history comparisons only consider runs with matching info.
+func SetRunInfo(provider, kubernetesVersion string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current.Provider = provider
+	current.KubernetesVersion = kubernetesVersion
+}
+
+// Record records a duration metric for the current run.
+func Record(name string, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current.Metrics[name] = d.Seconds()
+}
+
+// CountFailure increments the counter for a failure classification code.
+func CountFailure(category string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current.FailureCategories[category]++
+}
+
+// flushHooks run at the start of Flush, letting other packages export
+// run-scoped data alongside the metrics without every suite having to
+// know about them.
+var flushHooks []func()
+
+// OnFlush registers a hook to run whenever the run metrics are flushed.
+// Hooks are best-effort and must not block.
+func OnFlush(hook func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	flushHooks = append(flushHooks, hook)
+}
+
+// Flush writes the current run's metrics as an artifact, appends them to
+// the history file (if configured), and writes a regressions artifact for
+// any metric that regressed significantly vs the trailing runs.
+func Flush() error {
+	mu.Lock()
+	current.Timestamp = time.Now().UTC()
+	run := current
+	hooks := flushHooks
+	mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	if len(run.Metrics) == 0 && len(run.FailureCategories) == 0 {
+		return nil
+	}
+
+	if err := artifacts.WriteJSON("run-metrics.json", run); err != nil {
+		return err
+	}
+
+	historyPath := os.Getenv(constants.MetricsHistoryEnvVar)
+	if historyPath == "" {
+		return nil
+	}
+
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+
+	regressions := detectRegressions(history, run, trailingRuns)
+	if len(regressions) > 0 {
+		if err := artifacts.WriteJSON("metrics-regressions.json", regressions); err != nil {
+			return err
+		}
+	}
+
+	// A straight diff against the immediately previous run of this cell
+	// complements the statistical check: it catches the "what changed since
+	// last night" cases triage actually asks about.
+	if diff := diffAgainstPrevious(history, run); diff != nil {
+		if err := artifacts.WriteJSON("run-diff.json", diff); err != nil {
+			return err
+		}
+	}
+
+	return appendHistory(historyPath, run)
+}
+
+// loadHistory reads the history file, one JSON run per line. A missing
+// file yields an empty history.
+func loadHistory(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "opening metrics history %s", path)
+	}
+	defer f.Close()
+
+	var history []Run
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, errors.Wrapf(err, "unmarshaling metrics history %s", path)
+		}
+		history = append(history, run)
+	}
+
+	return history, errors.Wrapf(scanner.Err(), "reading metrics history %s", path)
+}
+
+func appendHistory(path string, run Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return errors.Wrap(err, "marshaling run metrics")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening metrics history %s", path)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\n", data)
+	return errors.Wrapf(err, "appending to metrics history %s", path)
+}
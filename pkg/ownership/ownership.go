@@ -0,0 +1,66 @@
+// Package ownership stamps clusters with metadata about the run that
+// created them, so leftover clusters in the shared org can always be
+// traced back to a CI job (or a human) and safely deleted.
+package ownership
+
+import (
+	"fmt"
+	"os"
+)
+
+// Label keys applied to every cluster the harness creates.
+const (
+	BuildURLLabel  = "e2e.containership.io/build-url"
+	BranchLabel    = "e2e.containership.io/branch"
+	InitiatorLabel = "e2e.containership.io/initiator"
+)
+
+// Labels returns ownership labels for the current run: the CI job URL and
+// git branch when running in CI, and the initiating user otherwise. Empty
+// values are omitted.
+func Labels() map[string]string {
+	labels := map[string]string{}
+
+	if buildURL := os.Getenv("BUILD_URL"); buildURL != "" {
+		labels[BuildURLLabel] = buildURL
+	}
+	if branch := os.Getenv("GIT_BRANCH"); branch != "" {
+		labels[BranchLabel] = branch
+	}
+
+	initiator := os.Getenv("USER")
+	if os.Getenv("BUILD_URL") != "" {
+		initiator = "ci"
+	}
+	if initiator == "" {
+		hostname, _ := os.Hostname()
+		initiator = fmt.Sprintf("unknown@%s", hostname)
+	}
+	labels[InitiatorLabel] = initiator
+
+	return labels
+}
+
+// Describe formats ownership labels for human-readable reports.
+func Describe(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "unknown owner"
+	}
+
+	out := fmt.Sprintf("initiator=%s", orUnknown(labels[InitiatorLabel]))
+	if branch := labels[BranchLabel]; branch != "" {
+		out += fmt.Sprintf(" branch=%s", branch)
+	}
+	if buildURL := labels[BuildURLLabel]; buildURL != "" {
+		out += fmt.Sprintf(" build=%s", buildURL)
+	}
+
+	return out
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+// Package progress optionally serves a live JSON snapshot of the run over
+// HTTP, so CI dashboards or humans can check on a 40-minute run without
+// tailing logs. It is enabled by passing --status-addr to the suite binary.
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// statusAddr enables the progress server when set, e.g. --status-addr=:8479.
+var statusAddr = flag.String("status-addr", "", "address to serve live run progress JSON on (disabled when empty)")
+
+// Snapshot is the state served to clients.
+type Snapshot struct {
+	Suite     string    `json:"suite,omitempty"`
+	Spec      string    `json:"spec,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// ElapsedSeconds is computed at serve time.
+	ElapsedSeconds int `json:"elapsed_seconds"`
+	// ClusterStatus and NodePoolStatuses are the last statuses observed by
+	// the timeline poller.
+	ClusterStatus    string            `json:"cluster_status,omitempty"`
+	NodePoolStatuses map[string]string `json:"node_pool_statuses,omitempty"`
+	ObservedAt       time.Time         `json:"observed_at,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	current Snapshot
+	server  *http.Server
+)
+
+// Start serves the progress endpoint if --status-addr was given. It is a
+// no-op otherwise, so suites can call it unconditionally.
+func Start() {
+	if *statusAddr == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if server != nil {
+		return
+	}
+
+	current.StartedAt = time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", serve)
+	server = &http.Server{Addr: *statusAddr, Handler: mux}
+
+	go func() {
+		//nolint:errcheck - a dead progress server must not affect the run
+		server.ListenAndServe()
+	}()
+}
+
+// Stop shuts the progress server down if it was started.
+func Stop() {
+	mu.Lock()
+	srv := server
+	server = nil
+	mu.Unlock()
+
+	if srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		//nolint:errcheck
+		srv.Shutdown(ctx)
+	}
+}
+
+func serve(w http.ResponseWriter, _ *http.Request) {
+	mu.Lock()
+	snapshot := current
+	mu.Unlock()
+	snapshot.ElapsedSeconds = int(time.Since(snapshot.StartedAt).Seconds())
+
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// SetSuite records the suite currently running.
+func SetSuite(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Suite = name
+}
+
+// SetSpec records the spec currently running; clear with an empty string.
+func SetSpec(fullText string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Spec = fullText
+}
+
+// SetClusterState records the latest observed cluster and node pool
+// statuses, fed by the timeline poller.
+func SetClusterState(clusterStatus string, nodePoolStatuses map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.ClusterStatus = clusterStatus
+	current.NodePoolStatuses = nodePoolStatuses
+	current.ObservedAt = time.Now()
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// The built-in providers supported by CKE.
+func init() {
+	Register(Definition{
+		Name: DigitalOcean,
+		Options: func(opts *Options) (json.RawMessage, error) {
+			if opts.DigitalOcean == nil {
+				return nil, nil
+			}
+			return marshalOptions(DigitalOcean, opts.DigitalOcean)
+		},
+	})
+
+	Register(Definition{
+		Name: AmazonWebServices,
+		Options: func(opts *Options) (json.RawMessage, error) {
+			if opts.AWS == nil {
+				return nil, nil
+			}
+			if opts.AWS.InstanceProfile == "" {
+				return nil, errors.New("amazon_web_services options require instance_profile")
+			}
+			return marshalOptions(AmazonWebServices, opts.AWS)
+		},
+	})
+
+	Register(Definition{
+		Name: Azure,
+		Options: func(opts *Options) (json.RawMessage, error) {
+			if opts.Azure == nil {
+				return nil, nil
+			}
+			if opts.Azure.ResourceGroup == "" {
+				return nil, errors.New("azure options require resource_group")
+			}
+			return marshalOptions(Azure, opts.Azure)
+		},
+	})
+
+	Register(Definition{
+		Name: Google,
+		Options: func(opts *Options) (json.RawMessage, error) {
+			if opts.Google == nil {
+				return nil, nil
+			}
+			if opts.Google.Project == "" {
+				return nil, errors.New("google options require project")
+			}
+			return marshalOptions(Google, opts.Google)
+		},
+	})
+
+	Register(Definition{
+		Name: Packet,
+		Options: func(opts *Options) (json.RawMessage, error) {
+			if opts.Packet == nil {
+				return nil, nil
+			}
+			if opts.Packet.ProjectID == "" {
+				return nil, errors.New("packet options require project_id")
+			}
+			return marshalOptions(Packet, opts.Packet)
+		},
+	})
+}
+
+func marshalOptions(provider string, section interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(section)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshaling %s options", provider)
+	}
+
+	return data, nil
+}
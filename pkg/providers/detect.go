@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+)
+
+// Detect returns the provider a cluster runs on, preferring the provision
+// API and falling back to the provider scheme in the nodes' provider IDs
+// (e.g. "digitalocean://1234") when the API doesn't know. It returns the
+// empty string when neither source identifies a provider.
+func Detect(ctx context.Context, client *cloud.Client, kube kubernetes.Interface, clusterID string) string {
+	cluster, err := client.GetCluster(clusterID)
+	if err == nil && cluster.ProviderName != "" {
+		return cluster.ProviderName
+	}
+
+	nodes, err := kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, node := range nodes.Items {
+		if i := strings.Index(node.Spec.ProviderID, "://"); i > 0 {
+			return node.Spec.ProviderID[:i]
+		}
+	}
+	return ""
+}
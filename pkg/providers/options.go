@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// DigitalOceanOptions are cluster create options specific to DigitalOcean.
+type DigitalOceanOptions struct {
+	Region string `json:"region,omitempty"`
+}
+
+// AWSOptions are cluster create options specific to AWS.
+type AWSOptions struct {
+	InstanceProfile string `json:"instance_profile,omitempty"`
+	Region          string `json:"region,omitempty"`
+}
+
+// AzureOptions are cluster create options specific to Azure.
+type AzureOptions struct {
+	ResourceGroup string `json:"resource_group,omitempty"`
+	Location      string `json:"location,omitempty"`
+}
+
+// GoogleOptions are cluster create options specific to Google Cloud.
+type GoogleOptions struct {
+	Project string `json:"project,omitempty"`
+	Zone    string `json:"zone,omitempty"`
+}
+
+// PacketOptions are cluster create options specific to Packet.
+type PacketOptions struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Facility  string `json:"facility,omitempty"`
+}
+
+// Options holds provider-specific cluster create options, keyed by provider
+// name as known to the provision API. Only the section matching the
+// provider under test is used for a given run.
+type Options struct {
+	DigitalOcean *DigitalOceanOptions `json:"digital_ocean,omitempty"`
+	AWS          *AWSOptions          `json:"amazon_web_services,omitempty"`
+	Azure        *AzureOptions        `json:"azure,omitempty"`
+	Google       *GoogleOptions       `json:"google,omitempty"`
+	Packet       *PacketOptions       `json:"packet,omitempty"`
+}
+
+// LoadOptions reads provider options from a YAML file. A missing path
+// returns empty options so runs on provider defaults need no config.
+func LoadOptions(path string) (*Options, error) {
+	if path == "" {
+		return &Options{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading provider options file %s", path)
+	}
+
+	var options Options
+	if err := yaml.Unmarshal(data, &options); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling provider options file %s", path)
+	}
+
+	return &options, nil
+}
+
+// LoadOptionsFromEnv loads provider options from the file named by the
+// environment, or empty options if the variable is unset.
+func LoadOptionsFromEnv() (*Options, error) {
+	return LoadOptions(os.Getenv(constants.ProviderOptionsFileEnvVar))
+}
+
+// ForProvider validates and marshals the options section for the named
+// provider, ready to attach to a cluster create request. It returns nil if
+// no options are configured for the provider, so defaults still apply.
+func (o *Options) ForProvider(name string) (json.RawMessage, error) {
+	definition, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return definition.Options(o)
+}
@@ -0,0 +1,59 @@
+// Package providers holds per-provider knowledge for the e2e suites:
+// which providers are supported and how their template requests are
+// defaulted and validated.
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+)
+
+// Provider names as known to the provision API.
+const (
+	DigitalOcean      = "digital_ocean"
+	AmazonWebServices = "amazon_web_services"
+	Azure             = "azure"
+	Google            = "google"
+	Packet            = "packet"
+)
+
+// defaultEngine is the engine used when a template doesn't specify one.
+const defaultEngine = "containership_kubernetes_engine"
+
+// DefaultAndValidate applies provider defaults to a template create request
+// and validates that it is well-formed enough to submit to the provision
+// API. It mutates req in place.
+func DefaultAndValidate(req *cloud.CreateTemplateRequest) error {
+	if req == nil {
+		return errors.New("template request is nil")
+	}
+
+	if req.ProviderName == "" {
+		return errors.New("template has no provider_name")
+	}
+	if _, err := Get(req.ProviderName); err != nil {
+		return err
+	}
+
+	if req.Engine == "" {
+		req.Engine = defaultEngine
+	}
+
+	if len(req.Configuration) == 0 || bytes.Equal(req.Configuration, []byte("null")) {
+		return errors.Errorf("template for provider %s has no configuration", req.ProviderName)
+	}
+
+	var configuration map[string]json.RawMessage
+	if err := json.Unmarshal(req.Configuration, &configuration); err != nil {
+		return errors.Wrapf(err, "template configuration for provider %s is not an object", req.ProviderName)
+	}
+	if len(configuration["resource"]) == 0 {
+		return errors.Errorf("template configuration for provider %s has no resource section", req.ProviderName)
+	}
+
+	return nil
+}
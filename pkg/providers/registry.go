@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Definition is everything the suites need to know about a provider. New
+// providers (e.g. Linode, vSphere) are added with a single Register call
+// and discovered dynamically from there.
+type Definition struct {
+	// Name is the provider name as known to the provision API.
+	Name string
+	// Options validates and marshals the cluster create options section
+	// for this provider, returning nil if none are configured so provider
+	// defaults still apply.
+	Options func(opts *Options) (json.RawMessage, error)
+}
+
+var registry = map[string]Definition{}
+
+// Register adds a provider definition to the registry. It panics on
+// duplicate registration since that is always a programming error.
+func Register(d Definition) {
+	if d.Name == "" {
+		panic("providers: registering definition with empty name")
+	}
+	if _, exists := registry[d.Name]; exists {
+		panic(fmt.Sprintf("providers: duplicate registration of provider %q", d.Name))
+	}
+
+	registry[d.Name] = d
+}
+
+// Get returns the definition for the named provider, or an error listing
+// the registered providers.
+func Get(name string) (Definition, error) {
+	d, ok := registry[name]
+	if !ok {
+		return Definition{}, errors.Errorf("unsupported provider %q (registered providers: %s)",
+			name, strings.Join(Names(), ", "))
+	}
+
+	return d, nil
+}
+
+// Names returns the sorted names of all registered providers.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
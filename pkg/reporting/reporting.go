@@ -0,0 +1,160 @@
+// Package reporting emits machine-readable results for CI. Jenkins can't
+// parse raw ginkgo output, so every suite runs with a JUnit XML reporter
+// plus a JSON summary reporter, both writing into --output-dir (or
+// E2E_OUTPUT_DIR). Suites opt in by running their specs through
+// RunSpecsWithDefaultAndCustomReporters with Reporters().
+package reporting
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/config"
+	"github.com/onsi/ginkgo/reporters"
+	"github.com/onsi/ginkgo/types"
+
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var outputDirFlag = flag.String("output-dir", "",
+	"directory for JUnit XML and JSON result output (overrides E2E_OUTPUT_DIR)")
+
+// Reporters returns the reporters the given suite should run with. It
+// returns nil when no output directory is configured, which ginkgo treats
+// as "no extra reporters" — local runs stay unchanged.
+func Reporters(suite string) []ginkgo.Reporter {
+	dir := *outputDirFlag
+	if dir == "" {
+		dir = os.Getenv(constants.OutputDirEnvVar)
+	}
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "reporting: creating output dir %s: %v\n", dir, err)
+		return nil
+	}
+
+	// Parallel ginkgo nodes each run their own reporters; suffix the node
+	// number so they don't clobber each other's files.
+	node := config.GinkgoConfig.ParallelNode
+	return []ginkgo.Reporter{
+		reporters.NewJUnitReporter(filepath.Join(dir, fmt.Sprintf("junit-%s-%d.xml", suite, node))),
+		newJSONReporter(suite, filepath.Join(dir, fmt.Sprintf("results-%s-%d.json", suite, node))),
+	}
+}
+
+// SpecResult is one spec's outcome in the JSON summary.
+type SpecResult struct {
+	Spec            string  `json:"spec"`
+	State           string  `json:"state"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	FailureMessage  string  `json:"failure_message,omitempty"`
+}
+
+// Summary is the JSON document written per suite run.
+type Summary struct {
+	Suite     string `json:"suite"`
+	ClusterID string `json:"cluster_id,omitempty"`
+	// Seed is the ginkgo random seed the suite ran with; rerunning with
+	// --ginkgo-seed set to it reproduces the spec ordering.
+	Seed            int64        `json:"seed"`
+	StartedAt       string       `json:"started_at"`
+	DurationSeconds float64      `json:"duration_seconds"`
+	Passed          int          `json:"passed"`
+	Failed          int          `json:"failed"`
+	Skipped         int          `json:"skipped"`
+	Specs           []SpecResult `json:"specs"`
+}
+
+// jsonReporter collects spec outcomes and writes the Summary when the
+// suite ends. It implements ginkgo's Reporter interface.
+type jsonReporter struct {
+	suite   string
+	path    string
+	seed    int64
+	started time.Time
+	specs   []SpecResult
+}
+
+func newJSONReporter(suite, path string) *jsonReporter {
+	return &jsonReporter{suite: suite, path: path}
+}
+
+func (r *jsonReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+	r.seed = config.RandomSeed
+	r.started = time.Now()
+}
+
+func (r *jsonReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {}
+
+func (r *jsonReporter) SpecWillRun(specSummary *types.SpecSummary) {}
+
+func (r *jsonReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	result := SpecResult{
+		Spec:            strings.Join(specSummary.ComponentTexts[1:], " "),
+		State:           stateString(specSummary.State),
+		DurationSeconds: specSummary.RunTime.Seconds(),
+	}
+	if specSummary.State.IsFailure() {
+		result.FailureMessage = specSummary.Failure.Message
+	}
+
+	r.specs = append(r.specs, result)
+}
+
+func (r *jsonReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {}
+
+func (r *jsonReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	out := Summary{
+		Suite: r.suite,
+		// The cluster ID is only known once the suite's setup has resolved
+		// it, so it is read at the end rather than at construction.
+		ClusterID:       testcontext.ClusterID(),
+		Seed:            r.seed,
+		StartedAt:       r.started.UTC().Format(time.RFC3339),
+		DurationSeconds: summary.RunTime.Seconds(),
+		Passed:          summary.NumberOfPassedSpecs,
+		Failed:          summary.NumberOfFailedSpecs,
+		Skipped:         summary.NumberOfSkippedSpecs,
+		Specs:           r.specs,
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reporting: marshaling summary: %v\n", err)
+		return
+	}
+
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "reporting: writing %s: %v\n", r.path, err)
+	}
+}
+
+// stateString maps a ginkgo spec state to a stable string for the summary.
+func stateString(state types.SpecState) string {
+	switch state {
+	case types.SpecStatePassed:
+		return "passed"
+	case types.SpecStateFailed:
+		return "failed"
+	case types.SpecStatePanicked:
+		return "panicked"
+	case types.SpecStateTimedOut:
+		return "timed_out"
+	case types.SpecStateSkipped:
+		return "skipped"
+	case types.SpecStatePending:
+		return "pending"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,16 @@
+// Package runmode holds run-wide mode flags shared by all suites.
+package runmode
+
+import "flag"
+
+// readOnly gates mutating suites. In read-only mode only non-mutating
+// checks (cluster status, versions, connectivity) run, letting SREs point
+// the harness at a production cluster for post-deploy verification
+// without risk.
+var readOnly = flag.Bool("read-only", false, "run only non-mutating checks against an existing cluster")
+
+// ReadOnly returns true if the run must not mutate any cluster or org
+// state.
+func ReadOnly() bool {
+	return *readOnly
+}
@@ -0,0 +1,140 @@
+// Package severity lets individual checks run as warnings instead of hard
+// failures. A check carries a default severity in code; a config file can
+// override it per deployment, so a performance SLO or hardening check that
+// is known-loose on some provider can be landed at warn there without
+// turning every nightly run red, then promoted to fail once it holds.
+// Warned checks are recorded to a report artifact so they stay visible.
+package severity
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/onsi/ginkgo"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/log"
+)
+
+// Severity is how a failed check is surfaced.
+type Severity string
+
+const (
+	// Warn records the failure and lets the spec continue.
+	Warn Severity = "warn"
+	// Fail fails the spec, like a plain assertion.
+	Fail Severity = "fail"
+)
+
+// Config maps check names to severity overrides.
+type Config struct {
+	Checks map[string]Severity `json:"checks"`
+}
+
+// warning is one failed warn-severity check.
+type warning struct {
+	Check   string `json:"check"`
+	Spec    string `json:"spec,omitempty"`
+	Message string `json:"message"`
+}
+
+var (
+	mu       sync.Mutex
+	warnings []warning
+
+	loadOnce sync.Once
+	loaded   *Config
+)
+
+// Load reads a severity config file. A missing path returns an empty
+// config so the code-side defaults apply everywhere.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading severity config %s", path)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling severity config %s", path)
+	}
+
+	for check, severity := range config.Checks {
+		if severity != Warn && severity != Fail {
+			return nil, errors.Errorf("severity config %s: check %s has invalid severity %q", path, check, severity)
+		}
+	}
+
+	return &config, nil
+}
+
+// LoadFromEnv loads the severity config named by the environment, or an
+// empty config if the variable is unset.
+func LoadFromEnv() (*Config, error) {
+	return Load(os.Getenv(constants.SeverityFileEnvVar))
+}
+
+// For returns the severity configured for the check, or fallback when the
+// config doesn't mention it.
+func (c *Config) For(check string, fallback Severity) Severity {
+	if severity, ok := c.Checks[check]; ok {
+		return severity
+	}
+	return fallback
+}
+
+// config loads the severity config once on first use. An unreadable config
+// is logged and ignored so a typo demotes nothing silently: every check
+// runs at its code-side default.
+func config() *Config {
+	loadOnce.Do(func() {
+		var err error
+		loaded, err = LoadFromEnv()
+		if err != nil {
+			log.Errorf("severity config ignored, using check defaults: %v", err)
+			loaded = &Config{}
+		}
+	})
+	return loaded
+}
+
+// Check asserts a named check at its configured severity. A passing check
+// is a no-op. A failed fail-severity check fails the spec; a failed
+// warn-severity check logs a warning, records it to the severity report
+// artifact, and lets the spec continue.
+func Check(check string, fallback Severity, passed bool, format string, args ...interface{}) {
+	if passed {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if config().For(check, fallback) == Fail {
+		ginkgo.Fail(fmt.Sprintf("%s: %s", check, message), 1)
+		return
+	}
+
+	log.Warnf("check %s failed (severity warn): %s", check, message)
+	record(warning{
+		Check:   check,
+		Spec:    ginkgo.CurrentGinkgoTestDescription().FullTestText,
+		Message: message,
+	})
+}
+
+func record(w warning) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	warnings = append(warnings, w)
+	// Rewrite the report on every warning so it survives an aborted run.
+	//nolint:errcheck - reporting must not escalate a warning into a failure
+	artifacts.WriteJSON("severity-warnings.json", warnings)
+}
@@ -0,0 +1,43 @@
+// Package state persists the IDs that tie pipeline stages together. The
+// provision suite writes the state file, later suites and the cleanup
+// stage read it, so the orchestrator can sequence them without scraping
+// output or hand-rolled shell glue.
+package state
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// State identifies the cluster a pipeline run is operating on.
+type State struct {
+	ClusterID  string `json:"cluster_id"`
+	TemplateID string `json:"template_id"`
+}
+
+// Save writes the state file.
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling pipeline state")
+	}
+
+	return errors.Wrapf(os.WriteFile(path, data, 0644), "writing pipeline state %s", path)
+}
+
+// Load reads the state file.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading pipeline state %s", path)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling pipeline state %s", path)
+	}
+
+	return &s, nil
+}
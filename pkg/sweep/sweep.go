@@ -0,0 +1,149 @@
+// Package sweep identifies and removes orphaned e2e clusters and templates
+// from the test organization. Failed CI runs regularly leak both; the
+// sweeper finds resources the harness created (by the e2e- naming
+// convention and ownership labels) that are older than a configurable age
+// and deletes them.
+package sweep
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/ownership"
+)
+
+// NamePrefix marks resources created by the harness.
+const NamePrefix = "e2e-"
+
+// SeedDescriptionPrefix marks standing fixtures created by the seed
+// command. They are deliberately long-lived, so the sweeper leaves them
+// alone despite the e2e prefix.
+const SeedDescriptionPrefix = "e2e-seed: "
+
+// SeedSSHKeyName names the standing SSH key the seed command uploads; like
+// seeded templates, it survives resets and sweeps.
+const SeedSSHKeyName = cloud.SSHKeyNamePrefix + "seed"
+
+// Candidate is one orphaned resource the sweeper would remove.
+type Candidate struct {
+	Kind  string        `json:"kind"`
+	ID    string        `json:"id"`
+	Name  string        `json:"name"`
+	Age   time.Duration `json:"age_ns"`
+	Owner string        `json:"owner,omitempty"`
+}
+
+// FindOrphans lists e2e-created clusters and templates older than maxAge.
+// Resources whose age cannot be determined are skipped: deleting something
+// we can't date is how sweepers eat live clusters.
+func FindOrphans(client *cloud.Client, maxAge time.Duration) ([]Candidate, error) {
+	var candidates []Candidate
+
+	clusters, err := client.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if !harnessCluster(cluster) {
+			continue
+		}
+		age, ok := age(cluster.CreatedAt)
+		if !ok || age < maxAge {
+			continue
+		}
+
+		candidates = append(candidates, Candidate{
+			Kind:  "cluster",
+			ID:    cluster.ID,
+			Name:  cluster.Name,
+			Age:   age,
+			Owner: ownership.Describe(cluster.Labels),
+		})
+	}
+
+	templates, err := client.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if !strings.HasPrefix(template.Description, NamePrefix) &&
+			!strings.HasPrefix(template.Description, "e2e ") {
+			continue
+		}
+		if strings.HasPrefix(template.Description, SeedDescriptionPrefix) {
+			continue
+		}
+		age, ok := age(template.CreatedAt)
+		if !ok || age < maxAge {
+			continue
+		}
+
+		candidates = append(candidates, Candidate{
+			Kind: "template",
+			ID:   template.ID,
+			Name: template.Description,
+			Age:  age,
+		})
+	}
+
+	return candidates, nil
+}
+
+// Remove deletes the given candidates: clusters first (polling each until
+// fully deleted), then templates, since a template can't be deleted while
+// a cluster still references it.
+func Remove(client *cloud.Client, candidates []Candidate, deleteTimeout time.Duration) error {
+	for _, candidate := range candidates {
+		if candidate.Kind != "cluster" {
+			continue
+		}
+		if err := client.DeleteCluster(candidate.ID); err != nil {
+			return errors.Wrapf(err, "deleting cluster %s (%s)", candidate.ID, candidate.Name)
+		}
+		if err := client.WaitForClusterDeleted(candidate.ID, deleteTimeout); err != nil {
+			return errors.Wrapf(err, "waiting for cluster %s (%s) to delete", candidate.ID, candidate.Name)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Kind != "template" {
+			continue
+		}
+		if err := client.DeleteTemplate(candidate.ID); err != nil {
+			return errors.Wrapf(err, "deleting template %s (%s)", candidate.ID, candidate.Name)
+		}
+	}
+
+	return nil
+}
+
+// harnessCluster reports whether the cluster was created by the harness,
+// by name prefix or by the ownership labels every run stamps.
+func harnessCluster(cluster cloud.Cluster) bool {
+	if strings.HasPrefix(cluster.Name, NamePrefix) {
+		return true
+	}
+	_, ok := cluster.Labels[ownership.InitiatorLabel]
+	return ok
+}
+
+// age parses a created-at timestamp (RFC3339 or epoch milliseconds) and
+// returns how old the resource is.
+func age(createdAt string) (time.Duration, bool) {
+	if createdAt == "" {
+		return 0, false
+	}
+
+	if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+		return time.Since(t), true
+	}
+	if millis, err := strconv.ParseInt(createdAt, 10, 64); err == nil {
+		return time.Since(time.Unix(0, millis*int64(time.Millisecond))), true
+	}
+
+	return 0, false
+}
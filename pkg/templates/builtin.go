@@ -0,0 +1,49 @@
+package templates
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// builtinFS is the default template library shipped inside the binary:
+// one directory per provider, one file per OS image, so the standard e2e
+// flow runs without maintaining an external template file. The files
+// follow the same variable conventions as the templates/ directory.
+//
+//go:embed builtin
+var builtinFS embed.FS
+
+// RenderBuiltin renders the named built-in template with the given
+// variables. Names are provider/os, e.g. "aws/ubuntu1804"; see Builtins
+// for everything available.
+func RenderBuiltin(name string, vars map[string]string) (*Result, error) {
+	builtinPath := path.Join("builtin", name+".yaml")
+	data, err := builtinFS.ReadFile(builtinPath)
+	if err != nil {
+		return nil, errors.Errorf("unknown built-in template %q (available: %s)",
+			name, strings.Join(Builtins(), ", "))
+	}
+
+	return render(builtinPath, data, vars)
+}
+
+// Builtins returns the names of all built-in templates, sorted.
+func Builtins() []string {
+	var names []string
+	//nolint:errcheck - walking an embedded FS cannot fail
+	fs.WalkDir(builtinFS, "builtin", func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entryPath, ".yaml") {
+			return err
+		}
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(entryPath, "builtin/"), ".yaml"))
+		return nil
+	})
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/containership/e2e-test/pkg/providers"
+)
+
+// TestBuiltinCorpus renders every built-in template against the same input
+// matrix as the file corpus and validates the result per provider, so a
+// broken built-in fails on every PR just like a broken file template.
+func TestBuiltinCorpus(t *testing.T) {
+	names := Builtins()
+	if len(names) == 0 {
+		t.Fatal("no built-in templates are embedded")
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			for _, vars := range inputMatrix {
+				result, err := RenderBuiltin(name, vars)
+				if err != nil {
+					t.Fatalf("rendering with vars %v: %v", vars, err)
+				}
+
+				if err := providers.DefaultAndValidate(result.Request); err != nil {
+					t.Errorf("validating render with vars %v: %v", vars, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderBuiltinUnknownName(t *testing.T) {
+	if _, err := RenderBuiltin("aws/windows95", inputMatrix[0]); err == nil {
+		t.Fatal("expected an error for an unknown built-in template name")
+	}
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/providers"
+)
+
+var update = flag.Bool("update", false, "update golden template files")
+
+// templatesDir is the shared template corpus shipped with the repo.
+const templatesDir = "../../templates"
+
+// inputMatrix is the set of variable maps every template is rendered with.
+// Every entry must carry the variables all templates reference; the first
+// entry is also the golden render.
+var inputMatrix = []map[string]string{
+	DefaultVars(constants.DefaultKubernetesVersion),
+	{"kubernetes_version": "1.14.8", "master_count": "3", "worker_count": "10"},
+}
+
+// TestTemplateCorpus renders every template in templates/ against a matrix
+// of inputs and validates the result per provider, catching template
+// breakage on every PR without touching the cloud. The no-variable render
+// is also compared against a golden copy under testdata/golden; run with
+// -update to regenerate after intentional template changes.
+func TestTemplateCorpus(t *testing.T) {
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil {
+		t.Fatalf("reading templates dir: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		path := filepath.Join(templatesDir, name)
+
+		t.Run(name, func(t *testing.T) {
+			for _, vars := range inputMatrix {
+				result, err := Render(path, vars)
+				if err != nil {
+					t.Fatalf("rendering with vars %v: %v", vars, err)
+				}
+
+				if err := providers.DefaultAndValidate(result.Request); err != nil {
+					t.Errorf("validating render with vars %v: %v", vars, err)
+				}
+			}
+
+			result, err := Render(path, inputMatrix[0])
+			if err != nil {
+				t.Fatalf("rendering: %v", err)
+			}
+
+			goldenPath := filepath.Join("testdata", "golden", name)
+			if *update {
+				if err := os.WriteFile(goldenPath, result.Rendered, 0644); err != nil {
+					t.Fatalf("updating golden file: %v", err)
+				}
+				return
+			}
+
+			golden, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("reading golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(result.Rendered, golden) {
+				t.Errorf("rendered template differs from golden file %s; run with -update if intentional", goldenPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package templates renders provision template files into create requests
+// for the provision API.
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+)
+
+// DefaultVars returns the baseline variable set every template is rendered
+// with. Callers override entries (e.g. pool counts) before rendering.
+func DefaultVars(kubernetesVersion string) map[string]string {
+	return map[string]string{
+		"kubernetes_version": kubernetesVersion,
+		"master_count":       "1",
+		"worker_count":       "2",
+	}
+}
+
+// Result is the output of rendering a template file.
+type Result struct {
+	// Request is the template create request parsed from the rendered
+	// template.
+	Request *cloud.CreateTemplateRequest
+	// Rendered is the fully rendered template text, before unmarshaling.
+	Rendered []byte
+	// Warnings are lint warnings from rendering, e.g. declared variables
+	// the template never references.
+	Warnings []string
+}
+
+// Render reads a template file, renders it as a Go text/template with the
+// given variables, and unmarshals the result into a template create
+// request. Rendering runs with missingkey=error so a reference to an
+// undefined variable fails immediately rather than causing a confusing
+// provisioning failure later. Lint warnings are returned for declared
+// variables the template never references, surfacing mistakes in new
+// provider templates.
+func Render(path string, vars map[string]string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading template file %s", path)
+	}
+
+	return render(path, data, vars)
+}
+
+// render is the shared rendering path for template files and the embedded
+// built-in library; path only flavors error messages and determines the
+// unmarshal format.
+func render(path string, data []byte, vars map[string]string) (*Result, error) {
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing template file %s", path)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		return nil, errors.Wrapf(err, "rendering template file %s", path)
+	}
+
+	req, err := unmarshalTemplate(path, rendered.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Request:  req,
+		Rendered: rendered.Bytes(),
+		Warnings: lintUnusedVars(string(data), path, vars),
+	}, nil
+}
+
+// unmarshalTemplate unmarshals a rendered template by file extension: .json
+// files (including raw CreateTemplateRequest exports from the UI) are
+// parsed as JSON, everything else as YAML.
+func unmarshalTemplate(path string, data []byte) (*cloud.CreateTemplateRequest, error) {
+	var req cloud.CreateTemplateRequest
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, errors.Wrapf(err, "unmarshaling JSON template file %s", path)
+		}
+		return &req, nil
+	}
+
+	if err := yaml.Unmarshal(data, &req); err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling template file %s", path)
+	}
+	return &req, nil
+}
+
+// lintUnusedVars warns about declared variables the template text never
+// references. Detection is textual (a reference looks like ".name" inside
+// an action), which is good enough to catch typos in new templates.
+func lintUnusedVars(text, path string, vars map[string]string) []string {
+	var warnings []string
+	for name := range vars {
+		if !strings.Contains(text, "."+name) {
+			warnings = append(warnings,
+				fmt.Sprintf("template %s: declared variable %q is never referenced", path, name))
+		}
+	}
+
+	return warnings
+}
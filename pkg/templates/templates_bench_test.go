@@ -0,0 +1,21 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// BenchmarkRender guards the template render+unmarshal path used on every
+// provision against performance regressions during framework refactors.
+func BenchmarkRender(b *testing.B) {
+	const path = "../../templates/digitalocean.yaml"
+	vars := DefaultVars(constants.DefaultKubernetesVersion)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Render(path, vars); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+)
+
+// Topology is a command-line override of the node pool shape in a parsed
+// template. It is applied to the parsed configuration after rendering, so
+// it works against any template file regardless of which variables the
+// file declares.
+type Topology struct {
+	// MasterCount and WorkerCount override the pool counts; zero keeps
+	// whatever the template rendered.
+	MasterCount int
+	WorkerCount int
+	// MasterInstanceSize and WorkerInstanceSize override the pools'
+	// instance sizes; empty keeps the template's.
+	MasterInstanceSize string
+	WorkerInstanceSize string
+}
+
+// sizeKeys are the per-provider spellings of a pool's instance size in
+// template configurations (DigitalOcean "size", AWS "instance_size" or
+// "instance_type", GCP "machine_type", Azure "vm_size", Packet "plan").
+// PatchTopology rewrites whichever key the pool already declares rather
+// than guessing the provider from the template.
+var sizeKeys = []string{"size", "instance_size", "instance_type", "machine_type", "vm_size", "plan"}
+
+// PatchTopology rewrites the node pool counts and instance sizes in a
+// parsed template's configuration in place. Pools are classified by name:
+// a resource whose name contains "master" is a master pool, everything
+// else is a worker pool, matching the master_pool/worker_pool convention
+// of the shipped templates. Requesting an instance size for a pool that
+// declares no recognized size key is an error rather than a silent no-op.
+func PatchTopology(req *cloud.CreateTemplateRequest, topology Topology) error {
+	var configuration map[string]interface{}
+	if err := json.Unmarshal(req.Configuration, &configuration); err != nil {
+		return errors.Wrap(err, "unmarshaling template configuration")
+	}
+
+	resources, ok := configuration["resource"].(map[string]interface{})
+	if !ok {
+		return errors.New("template configuration has no resource block to patch")
+	}
+
+	for _, pools := range resources {
+		poolsMap, ok := pools.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, pool := range poolsMap {
+			poolMap, ok := pool.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			count, size := topology.WorkerCount, topology.WorkerInstanceSize
+			if strings.Contains(name, "master") {
+				count, size = topology.MasterCount, topology.MasterInstanceSize
+			}
+
+			if count > 0 {
+				poolMap["count"] = count
+			}
+			if size != "" {
+				if err := setInstanceSize(poolMap, size); err != nil {
+					return errors.Wrapf(err, "patching pool %s", name)
+				}
+			}
+		}
+	}
+
+	patched, err := json.Marshal(configuration)
+	if err != nil {
+		return errors.Wrap(err, "marshaling patched template configuration")
+	}
+	req.Configuration = patched
+
+	return nil
+}
+
+func setInstanceSize(pool map[string]interface{}, size string) error {
+	for _, key := range sizeKeys {
+		if _, ok := pool[key]; ok {
+			pool[key] = size
+			return nil
+		}
+	}
+	return errors.Errorf("pool declares no recognized instance size key (looked for %s)",
+		strings.Join(sizeKeys, ", "))
+}
@@ -0,0 +1,111 @@
+package templates
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// poolFromConfiguration digs a pool's map out of a patched configuration.
+func poolFromConfiguration(t *testing.T, configuration []byte, pool string) map[string]interface{} {
+	t.Helper()
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(configuration, &parsed); err != nil {
+		t.Fatalf("unmarshaling configuration: %v", err)
+	}
+
+	resources := parsed["resource"].(map[string]interface{})
+	for _, pools := range resources {
+		if poolMap, ok := pools.(map[string]interface{})[pool]; ok {
+			return poolMap.(map[string]interface{})
+		}
+	}
+
+	t.Fatalf("pool %s not found in configuration", pool)
+	return nil
+}
+
+func TestPatchTopology(t *testing.T) {
+	result, err := Render(filepath.Join(templatesDir, "digitalocean.yaml"),
+		DefaultVars(constants.DefaultKubernetesVersion))
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	err = PatchTopology(result.Request, Topology{
+		MasterCount:        3,
+		WorkerCount:        5,
+		WorkerInstanceSize: "s-4vcpu-8gb",
+	})
+	if err != nil {
+		t.Fatalf("patching topology: %v", err)
+	}
+
+	masterPool := poolFromConfiguration(t, result.Request.Configuration, "master_pool")
+	if got := masterPool["count"]; got != float64(3) {
+		t.Errorf("master pool count = %v, want 3", got)
+	}
+	if got := masterPool["size"]; got != "s-2vcpu-4gb" {
+		t.Errorf("master pool size = %v, want the template's untouched s-2vcpu-4gb", got)
+	}
+
+	workerPool := poolFromConfiguration(t, result.Request.Configuration, "worker_pool")
+	if got := workerPool["count"]; got != float64(5) {
+		t.Errorf("worker pool count = %v, want 5", got)
+	}
+	if got := workerPool["size"]; got != "s-4vcpu-8gb" {
+		t.Errorf("worker pool size = %v, want s-4vcpu-8gb", got)
+	}
+}
+
+func TestPatchTopologyZeroValuesKeepTemplate(t *testing.T) {
+	result, err := Render(filepath.Join(templatesDir, "digitalocean.yaml"),
+		DefaultVars(constants.DefaultKubernetesVersion))
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	if err := PatchTopology(result.Request, Topology{}); err != nil {
+		t.Fatalf("patching topology: %v", err)
+	}
+
+	workerPool := poolFromConfiguration(t, result.Request.Configuration, "worker_pool")
+	if got := workerPool["count"]; got != float64(2) {
+		t.Errorf("worker pool count = %v, want the rendered default 2", got)
+	}
+	if got := workerPool["size"]; got != "s-2vcpu-4gb" {
+		t.Errorf("worker pool size = %v, want the template's s-2vcpu-4gb", got)
+	}
+}
+
+func TestPatchTopologyMissingSizeKey(t *testing.T) {
+	result, err := Render(filepath.Join(templatesDir, "digitalocean.yaml"),
+		DefaultVars(constants.DefaultKubernetesVersion))
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	// Strip the size keys so the override has nothing to rewrite.
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(result.Request.Configuration, &parsed); err != nil {
+		t.Fatalf("unmarshaling configuration: %v", err)
+	}
+	for _, pools := range parsed["resource"].(map[string]interface{}) {
+		for _, pool := range pools.(map[string]interface{}) {
+			delete(pool.(map[string]interface{}), "size")
+		}
+	}
+	stripped, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatalf("marshaling stripped configuration: %v", err)
+	}
+	result.Request.Configuration = stripped
+
+	err = PatchTopology(result.Request, Topology{WorkerInstanceSize: "s-4vcpu-8gb"})
+	if err == nil {
+		t.Fatal("expected an error patching an instance size into a pool with no size key")
+	}
+}
@@ -0,0 +1,48 @@
+package testcontext
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/k8s"
+)
+
+// Bootstrap builds the cloud client, resolves the cluster under test, and
+// builds its Kubernetes client, populating the shared context with all
+// three. It is the standard suite setup: every suite that attaches to an
+// existing cluster used to duplicate this block in its
+// SynchronizedBeforeSuite. The suite name only flavors error messages.
+func Bootstrap(suite string) error {
+	client, err := cloud.New()
+	if err != nil {
+		return err
+	}
+	SetCloudClient(client)
+
+	clusterID := ResolveClusterID()
+	if clusterID == "" {
+		return errors.Errorf("--cluster-id or %s must be set for the %s suite",
+			constants.ClusterIDEnvVar, suite)
+	}
+	SetClusterID(clusterID)
+
+	kubeClient, err := k8s.New(clusterID)
+	if err != nil {
+		return err
+	}
+	SetKubeClient(kubeClient)
+
+	return nil
+}
+
+// EnsureBootstrapped is Bootstrap for ginkgo's secondary parallel nodes:
+// the primary node runs both halves of SynchronizedBeforeSuite and is
+// already populated, so this is a no-op there and a full Bootstrap
+// everywhere else.
+func EnsureBootstrapped(suite string) error {
+	if CloudClient() != nil {
+		return nil
+	}
+	return Bootstrap(suite)
+}
@@ -0,0 +1,164 @@
+// Package testcontext holds state shared between the e2e suites, such as
+// the IDs of the cluster and template under test. Suites populate it during
+// setup and read it from specs. The context is write-once: setters validate
+// their input and panic on any attempt to change a value after it has been
+// set, so a suite can't silently repoint mid-run state that every spec
+// depends on.
+//
+// Access is synchronized. Background collectors (the timeline poller, the
+// progress heartbeat, diagnostics dumps) read the context from their own
+// goroutines while suite setup is still writing it, so unguarded globals
+// here are a data race waiting for -race to find it.
+package testcontext
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/log"
+)
+
+// clusterIDFlag attaches a suite to an already-running cluster, e.g. a
+// long-lived dev cluster, without going through the provision suite. It
+// overrides the environment variable the orchestrated pipeline sets.
+var clusterIDFlag = flag.String("cluster-id", "",
+	fmt.Sprintf("ID of an existing cluster to run against (overrides %s)", constants.ClusterIDEnvVar))
+
+// ResolveClusterID returns the cluster a suite should attach to: the
+// --cluster-id flag wins, then the environment variable. It returns ""
+// when neither is set, leaving the must-be-set assertion (and its suite-
+// specific message) to the caller.
+func ResolveClusterID() string {
+	if *clusterIDFlag != "" {
+		return *clusterIDFlag
+	}
+	return os.Getenv(constants.ClusterIDEnvVar)
+}
+
+var (
+	mu          sync.RWMutex
+	cloudClient *cloud.Client
+	kubeClient  kubernetes.Interface
+	clusterID   string
+	templateID  string
+)
+
+// runContext is cancelled on SIGINT/SIGTERM. It is created at package init
+// so the signal handler goroutine exists before any leak-check baseline is
+// taken.
+var runContext, _ = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+// Context returns the run-wide base context for API calls. It is cancelled
+// when CI kills the run with SIGINT or SIGTERM, so in-flight Kubernetes
+// calls abort promptly instead of holding teardown hostage on dead
+// connections. Callers needing a deadline should derive from it with
+// context.WithTimeout.
+func Context() context.Context {
+	return runContext
+}
+
+// SetCloudClient records the Containership Cloud API client for the run.
+// The client must not be nil and must not already be set to a different
+// client.
+func SetCloudClient(client *cloud.Client) {
+	if client == nil {
+		panic("testcontext: cloud client must not be nil")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if cloudClient != nil && cloudClient != client {
+		panic("testcontext: cloud client is already set; the context is write-once")
+	}
+	cloudClient = client
+}
+
+// CloudClient returns the Containership Cloud API client for the run, or
+// nil if suite setup has not run yet.
+func CloudClient() *cloud.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+	return cloudClient
+}
+
+// SetKubeClient records the Kubernetes client for the cluster under test.
+// The client must not be nil and must not already be set to a different
+// client.
+func SetKubeClient(client kubernetes.Interface) {
+	if client == nil {
+		panic("testcontext: Kubernetes client must not be nil")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if kubeClient != nil && kubeClient != client {
+		panic("testcontext: Kubernetes client is already set; the context is write-once")
+	}
+	kubeClient = client
+}
+
+// KubeClient returns the Kubernetes client for the cluster under test, or
+// nil if suite setup has not run yet.
+func KubeClient() kubernetes.Interface {
+	mu.RLock()
+	defer mu.RUnlock()
+	return kubeClient
+}
+
+// SetClusterID records the ID of the cluster under test. The ID is also
+// handed to the logger so subsequent entries are attributed to the
+// cluster.
+func SetClusterID(id string) {
+	setID("cluster", &clusterID, id)
+	if id != "" {
+		log.SetClusterID(id)
+	}
+}
+
+// ClusterID returns the ID of the cluster under test, or "" if suite setup
+// has not run yet.
+func ClusterID() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return clusterID
+}
+
+// SetTemplateID records the ID of the template the cluster was provisioned
+// from.
+func SetTemplateID(id string) {
+	setID("template", &templateID, id)
+}
+
+// TemplateID returns the ID of the template the cluster was provisioned
+// from, or "" if it is unknown.
+func TemplateID() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return templateID
+}
+
+// setID records an ID write-once. Setting an empty ID is a no-op so stages
+// that pass through unset environment values don't clobber a known ID, and
+// the redundant re-set on ginkgo's primary node (which runs both halves of
+// SynchronizedBeforeSuite) stays legal as long as the value is unchanged.
+func setID(what string, current *string, id string) {
+	if id == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if *current != "" && *current != id {
+		panic(fmt.Sprintf("testcontext: %s ID is already %s; the context is write-once", what, *current))
+	}
+	*current = id
+}
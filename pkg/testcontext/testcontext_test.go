@@ -0,0 +1,110 @@
+package testcontext
+
+import (
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// reset clears the shared context between test cases. Only tests may do
+// this; the production API is deliberately write-once.
+func reset() {
+	cloudClient = nil
+	kubeClient = nil
+	clusterID = ""
+	templateID = ""
+}
+
+func expectPanic(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic, got none")
+		}
+	}()
+	fn()
+}
+
+func TestIDsAreWriteOnce(t *testing.T) {
+	defer reset()
+	reset()
+
+	SetClusterID("cluster-a")
+	if got := ClusterID(); got != "cluster-a" {
+		t.Fatalf("ClusterID() = %q, want cluster-a", got)
+	}
+
+	// Re-setting the same value is legal: ginkgo's primary node runs both
+	// halves of SynchronizedBeforeSuite.
+	SetClusterID("cluster-a")
+
+	// An empty set must not clobber a known ID.
+	SetClusterID("")
+	if got := ClusterID(); got != "cluster-a" {
+		t.Fatalf("ClusterID() = %q after empty set, want cluster-a", got)
+	}
+
+	expectPanic(t, func() { SetClusterID("cluster-b") })
+
+	SetTemplateID("template-a")
+	expectPanic(t, func() { SetTemplateID("template-b") })
+}
+
+func TestClientsValidateAndAreWriteOnce(t *testing.T) {
+	defer reset()
+	reset()
+
+	expectPanic(t, func() { SetKubeClient(nil) })
+
+	first := fake.NewSimpleClientset()
+	SetKubeClient(first)
+	if KubeClient() != first {
+		t.Fatal("KubeClient() did not return the client that was set")
+	}
+
+	// Same client again is legal; a different one is a mutation.
+	SetKubeClient(first)
+	expectPanic(t, func() { SetKubeClient(fake.NewSimpleClientset()) })
+}
+
+// TestConcurrentAccess hammers the context from reader and writer
+// goroutines the way background collectors do; run with -race to make it
+// meaningful.
+func TestConcurrentAccess(t *testing.T) {
+	defer reset()
+	reset()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetClusterID("cluster-a")
+			SetTemplateID("template-a")
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = ClusterID()
+				_ = TemplateID()
+				_ = CloudClient()
+				_ = KubeClient()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ClusterID() != "cluster-a" || TemplateID() != "template-a" {
+		t.Fatal("concurrent setters lost a write")
+	}
+}
+
+func TestUnsetContextReturnsZeroValues(t *testing.T) {
+	defer reset()
+	reset()
+
+	if CloudClient() != nil || KubeClient() != nil || ClusterID() != "" || TemplateID() != "" {
+		t.Fatal("fresh context is not zero-valued")
+	}
+}
@@ -0,0 +1,117 @@
+// Package timeline provides a background poller that samples the status of
+// the cluster under test for the duration of a run and writes the samples
+// out as a timeline artifact, so post-hoc analysis can see exactly when
+// cloud-side transitions happened relative to test actions.
+package timeline
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/progress"
+)
+
+// Sample is a single observation of cluster and node pool statuses.
+type Sample struct {
+	Timestamp        time.Time         `json:"timestamp"`
+	ClusterStatus    string            `json:"cluster_status"`
+	NodePoolStatuses map[string]string `json:"node_pool_statuses,omitempty"`
+	Error            string            `json:"error,omitempty"`
+}
+
+// Poller periodically samples the status of a cluster and its node pools in
+// a background goroutine.
+type Poller struct {
+	client    *cloud.Client
+	clusterID string
+	interval  time.Duration
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// NewPoller constructs a Poller for the given cluster. It does not start
+// polling until Start is called.
+func NewPoller(client *cloud.Client, clusterID string, interval time.Duration) *Poller {
+	return &Poller{
+		client:    client,
+		clusterID: clusterID,
+		interval:  interval,
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// Start begins sampling in a background goroutine. It samples once
+// immediately so the timeline always has an initial entry.
+func (p *Poller) Start() {
+	go func() {
+		defer close(p.doneCh)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.sample()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.sample()
+			}
+		}
+	}()
+}
+
+// Stop stops the poller and blocks until the background goroutine exits.
+func (p *Poller) Stop() {
+	close(p.stopCh)
+	<-p.doneCh
+}
+
+// WriteArtifact writes the collected timeline as a JSON artifact named for
+// the cluster being polled.
+func (p *Poller) WriteArtifact() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return artifacts.WriteJSON(fmt.Sprintf("timeline-%s.json", p.clusterID), p.samples)
+}
+
+func (p *Poller) sample() {
+	s := Sample{
+		Timestamp: time.Now().UTC(),
+	}
+
+	cluster, err := p.client.GetCluster(p.clusterID)
+	if err != nil {
+		// Record the error rather than dropping the sample; gaps in the
+		// timeline are themselves interesting.
+		s.Error = err.Error()
+	} else {
+		s.ClusterStatus = cluster.Status.Type
+
+		nodePools, err := p.client.ListNodePools(p.clusterID)
+		if err != nil {
+			s.Error = err.Error()
+		} else {
+			s.NodePoolStatuses = make(map[string]string, len(nodePools))
+			for _, np := range nodePools {
+				s.NodePoolStatuses[np.ID] = np.Status.Type
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.samples = append(p.samples, s)
+	p.mu.Unlock()
+
+	// Feed the live progress endpoint, if one is serving.
+	progress.SetClusterState(s.ClusterStatus, s.NodePoolStatuses)
+}
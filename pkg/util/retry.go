@@ -0,0 +1,78 @@
+// Package util holds small helpers shared across suites that are too
+// general to live with any one subsystem.
+package util
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+)
+
+const (
+	// retryAttempts bounds how many times a cloud call is tried in total.
+	retryAttempts = 5
+
+	retryInitialBackoff = 2 * time.Second
+	retryMaxBackoff     = 30 * time.Second
+)
+
+// RetryCloudCall runs fn, retrying with exponential backoff and jitter as
+// long as it fails with a retryable cloud error. Transient 5xx and
+// rate-limit responses from the stage API would otherwise fail a spec
+// outright even though the very next call succeeds. Non-retryable errors
+// are returned immediately.
+func RetryCloudCall(fn func() error) error {
+	backoff := retryInitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryableCloudError(err) {
+			return err
+		}
+
+		if attempt == retryAttempts {
+			break
+		}
+
+		// Full jitter keeps parallel suites from retrying in lockstep
+		// against an API that just rate-limited all of them.
+		sleep := time.Duration(rand.Int63n(int64(backoff)))
+		fmt.Fprintf(ginkgo.GinkgoWriter, "retryable cloud error (attempt %d/%d, retrying in %s): %v\n",
+			attempt, retryAttempts, sleep.Round(time.Millisecond), err)
+		time.Sleep(sleep)
+
+		if backoff *= 2; backoff > retryMaxBackoff {
+			backoff = retryMaxBackoff
+		}
+	}
+
+	return errors.Wrapf(err, "cloud call failed after %d attempts", retryAttempts)
+}
+
+// IsRetryableCloudError returns true for cloud API failures that are worth
+// retrying in place: rate limiting, server-side 5xx responses, and network
+// timeouts. Anything else (4xx, malformed requests) fails fast.
+func IsRetryableCloudError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *cloud.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
@@ -0,0 +1,178 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/credentials"
+	"github.com/containership/e2e-test/pkg/providers"
+)
+
+// azureClusterIDTag is the tag CKE applies to all Azure resources it
+// creates for a cluster.
+const azureClusterIDTag = "containership-cluster-id"
+
+const azureManagementBaseURL = "https://management.azure.com"
+
+func init() {
+	register(providers.Azure, newAzureVerifier)
+}
+
+// azureVerifier checks Azure-side state via the ARM REST API. Azure leaks
+// are the most expensive ones we see, so cleanup verification matters as
+// much as provision verification here.
+type azureVerifier struct {
+	httpClient     *http.Client
+	subscriptionID string
+}
+
+// newAzureVerifier obtains an authenticated client from the credentials
+// broker, returning (nil, nil) if Azure credentials aren't configured.
+func newAzureVerifier() (Verifier, error) {
+	httpClient, subscriptionID, err := credentials.Azure()
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		return nil, nil
+	}
+
+	return &azureVerifier{
+		httpClient:     httpClient,
+		subscriptionID: subscriptionID,
+	}, nil
+}
+
+// Provider implements Verifier.
+func (v *azureVerifier) Provider() string {
+	return providers.Azure
+}
+
+// VerifyProvisioned implements Verifier. It checks that exactly one
+// resource group is tagged with the cluster ID and that it contains the
+// expected number of VMs and at least one NIC per VM, all tagged with the
+// cluster ID.
+func (v *azureVerifier) VerifyProvisioned(cluster *cloud.Cluster, nodePools []cloud.NodePool) error {
+	groups, err := v.resourceGroupsForCluster(cluster.ID)
+	if err != nil {
+		return err
+	}
+	if len(groups) != 1 {
+		return errors.Errorf("expected exactly 1 resource group tagged %s=%s, found %d",
+			azureClusterIDTag, cluster.ID, len(groups))
+	}
+	group := groups[0]
+
+	var expectedNodes int32
+	for _, np := range nodePools {
+		expectedNodes += np.Count
+	}
+
+	vms, err := v.listResources(group, "Microsoft.Compute/virtualMachines", "2019-07-01")
+	if err != nil {
+		return err
+	}
+	if int32(len(vms)) != expectedNodes {
+		return errors.Errorf("resource group %s has %d VMs, expected %d", group, len(vms), expectedNodes)
+	}
+	for _, vm := range vms {
+		if vm.Tags[azureClusterIDTag] != cluster.ID {
+			return errors.Errorf("VM %s is not tagged %s=%s", vm.Name, azureClusterIDTag, cluster.ID)
+		}
+	}
+
+	nics, err := v.listResources(group, "Microsoft.Network/networkInterfaces", "2019-11-01")
+	if err != nil {
+		return err
+	}
+	if len(nics) < len(vms) {
+		return errors.Errorf("resource group %s has %d NICs for %d VMs", group, len(nics), len(vms))
+	}
+
+	return nil
+}
+
+// VerifyCleanedUp implements Verifier. It checks that no resource group
+// tagged with the cluster ID remains.
+func (v *azureVerifier) VerifyCleanedUp(clusterID string) error {
+	groups, err := v.resourceGroupsForCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if len(groups) != 0 {
+		return errors.Errorf("resource groups still exist for cluster %s after cleanup: %s",
+			clusterID, strings.Join(groups, ", "))
+	}
+
+	return nil
+}
+
+// azureResource is the subset of an ARM resource the verifier cares about.
+type azureResource struct {
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags"`
+}
+
+func (v *azureVerifier) resourceGroupsForCluster(clusterID string) ([]string, error) {
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", azureClusterIDTag, clusterID)
+	u := fmt.Sprintf("%s/subscriptions/%s/resourcegroups?api-version=2019-10-01&$filter=%s",
+		azureManagementBaseURL, v.subscriptionID, url.QueryEscape(filter))
+
+	var result struct {
+		Value []azureResource `json:"value"`
+	}
+	if err := v.get(u, &result); err != nil {
+		return nil, errors.Wrap(err, "listing resource groups")
+	}
+
+	names := make([]string, 0, len(result.Value))
+	for _, group := range result.Value {
+		names = append(names, group.Name)
+	}
+
+	return names, nil
+}
+
+func (v *azureVerifier) listResources(resourceGroup, resourceType, apiVersion string) ([]azureResource, error) {
+	u := fmt.Sprintf("%s/subscriptions/%s/resourceGroups/%s/providers/%s?api-version=%s",
+		azureManagementBaseURL, v.subscriptionID, resourceGroup, resourceType, apiVersion)
+
+	var result struct {
+		Value []azureResource `json:"value"`
+	}
+	if err := v.get(u, &result); err != nil {
+		return nil, errors.Wrapf(err, "listing %s in resource group %s", resourceType, resourceGroup)
+	}
+
+	return result.Value, nil
+}
+
+func (v *azureVerifier) get(url string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: unexpected status %d: %s", url, resp.StatusCode, string(body))
+	}
+
+	return errors.Wrap(json.Unmarshal(body, out), "unmarshaling response body")
+}
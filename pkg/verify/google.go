@@ -0,0 +1,213 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/credentials"
+	"github.com/containership/e2e-test/pkg/providers"
+)
+
+// googleClusterIDLabel is the label CKE applies to all GCE resources it
+// creates for a cluster. Firewall rules can't be labeled, so they embed the
+// cluster ID in their names instead.
+const googleClusterIDLabel = "containership-cluster-id"
+
+const computeBaseURL = "https://compute.googleapis.com/compute/v1"
+
+func init() {
+	register(providers.Google, newGoogleVerifier)
+}
+
+// googleVerifier checks GCE-side state (instances, instance groups,
+// firewall rules) via the compute API.
+type googleVerifier struct {
+	httpClient *http.Client
+	project    string
+}
+
+// newGoogleVerifier obtains an authenticated client from the credentials
+// broker, returning (nil, nil) if GCP credentials aren't configured.
+func newGoogleVerifier() (Verifier, error) {
+	httpClient, project, err := credentials.Google()
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		return nil, nil
+	}
+
+	return &googleVerifier{
+		httpClient: httpClient,
+		project:    project,
+	}, nil
+}
+
+// Provider implements Verifier.
+func (v *googleVerifier) Provider() string {
+	return providers.Google
+}
+
+// VerifyProvisioned implements Verifier. It checks that the instances
+// created for the cluster match the template's node counts and are labeled
+// correctly, and that instance groups and firewall rules exist for the
+// cluster.
+func (v *googleVerifier) VerifyProvisioned(cluster *cloud.Cluster, nodePools []cloud.NodePool) error {
+	var expectedNodes int32
+	for _, np := range nodePools {
+		expectedNodes += np.Count
+	}
+
+	instances, err := v.aggregatedNames("instances", func(r gceResource) bool {
+		return r.Labels[googleClusterIDLabel] == cluster.ID
+	})
+	if err != nil {
+		return err
+	}
+	if int32(len(instances)) != expectedNodes {
+		return errors.Errorf("found %d instances labeled %s=%s, expected %d",
+			len(instances), googleClusterIDLabel, cluster.ID, expectedNodes)
+	}
+
+	groups, err := v.aggregatedNames("instanceGroups", func(r gceResource) bool {
+		return strings.Contains(r.Name, cluster.ID)
+	})
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return errors.Errorf("no instance groups found for cluster %s", cluster.ID)
+	}
+
+	firewalls, err := v.firewallsForCluster(cluster.ID)
+	if err != nil {
+		return err
+	}
+	if len(firewalls) == 0 {
+		return errors.Errorf("no firewall rules found for cluster %s", cluster.ID)
+	}
+
+	return nil
+}
+
+// VerifyCleanedUp implements Verifier. It checks that no instances,
+// instance groups, or firewall rules remain for the cluster.
+func (v *googleVerifier) VerifyCleanedUp(clusterID string) error {
+	instances, err := v.aggregatedNames("instances", func(r gceResource) bool {
+		return r.Labels[googleClusterIDLabel] == clusterID
+	})
+	if err != nil {
+		return err
+	}
+	if len(instances) != 0 {
+		return errors.Errorf("instances still exist for cluster %s after cleanup: %s",
+			clusterID, strings.Join(instances, ", "))
+	}
+
+	groups, err := v.aggregatedNames("instanceGroups", func(r gceResource) bool {
+		return strings.Contains(r.Name, clusterID)
+	})
+	if err != nil {
+		return err
+	}
+	if len(groups) != 0 {
+		return errors.Errorf("instance groups still exist for cluster %s after cleanup: %s",
+			clusterID, strings.Join(groups, ", "))
+	}
+
+	firewalls, err := v.firewallsForCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if len(firewalls) != 0 {
+		return errors.Errorf("firewall rules still exist for cluster %s after cleanup: %s",
+			clusterID, strings.Join(firewalls, ", "))
+	}
+
+	return nil
+}
+
+// gceResource is the subset of a compute resource the verifier cares about.
+type gceResource struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+// aggregatedNames lists a resource type across all zones and returns the
+// names of resources matching the predicate.
+func (v *googleVerifier) aggregatedNames(resource string, match func(gceResource) bool) ([]string, error) {
+	u := fmt.Sprintf("%s/projects/%s/aggregated/%s", computeBaseURL, v.project, resource)
+
+	// Each zone scope keys the resource list by the resource type and may
+	// also carry a "warning" object, so the list is extracted explicitly.
+	var result struct {
+		Items map[string]map[string]json.RawMessage `json:"items"`
+	}
+	if err := v.get(u, &result); err != nil {
+		return nil, errors.Wrapf(err, "listing %s", resource)
+	}
+
+	var names []string
+	for _, scope := range result.Items {
+		raw, ok := scope[resource]
+		if !ok {
+			continue
+		}
+
+		var resources []gceResource
+		if err := json.Unmarshal(raw, &resources); err != nil {
+			return nil, errors.Wrapf(err, "unmarshaling %s list", resource)
+		}
+		for _, r := range resources {
+			if match(r) {
+				names = append(names, r.Name)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+func (v *googleVerifier) firewallsForCluster(clusterID string) ([]string, error) {
+	u := fmt.Sprintf("%s/projects/%s/global/firewalls", computeBaseURL, v.project)
+
+	var result struct {
+		Items []gceResource `json:"items"`
+	}
+	if err := v.get(u, &result); err != nil {
+		return nil, errors.Wrap(err, "listing firewalls")
+	}
+
+	var names []string
+	for _, firewall := range result.Items {
+		if strings.Contains(firewall.Name, clusterID) {
+			names = append(names, firewall.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (v *googleVerifier) get(url string, out interface{}) error {
+	resp, err := v.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: unexpected status %d: %s", url, resp.StatusCode, string(body))
+	}
+
+	return errors.Wrap(json.Unmarshal(body, out), "unmarshaling response body")
+}
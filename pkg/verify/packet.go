@@ -0,0 +1,181 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/credentials"
+	"github.com/containership/e2e-test/pkg/providers"
+)
+
+const packetBaseURL = "https://api.packet.net"
+
+func init() {
+	register(providers.Packet, newPacketVerifier)
+}
+
+// packetVerifier checks bare-metal device state via the Packet API.
+// Bare-metal stock issues are a frequent cause of provisioning timeouts,
+// and device-level verification lets us distinguish them from real bugs.
+type packetVerifier struct {
+	httpClient *http.Client
+	projectID  string
+}
+
+// newPacketVerifier obtains an authenticated client from the credentials
+// broker, returning (nil, nil) if Packet credentials aren't configured.
+func newPacketVerifier() (Verifier, error) {
+	httpClient, projectID, err := credentials.Packet()
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		return nil, nil
+	}
+
+	return &packetVerifier{
+		httpClient: httpClient,
+		projectID:  projectID,
+	}, nil
+}
+
+// Provider implements Verifier.
+func (v *packetVerifier) Provider() string {
+	return providers.Packet
+}
+
+// VerifyProvisioned implements Verifier. It checks that the device count
+// matches the template's node counts, that every device is active, and
+// that all devices share a single plan and facility (compared against the
+// configured Packet options when present).
+func (v *packetVerifier) VerifyProvisioned(cluster *cloud.Cluster, nodePools []cloud.NodePool) error {
+	var expectedNodes int32
+	for _, np := range nodePools {
+		expectedNodes += np.Count
+	}
+
+	devices, err := v.devicesForCluster(cluster.ID)
+	if err != nil {
+		return err
+	}
+	if int32(len(devices)) != expectedNodes {
+		return errors.Errorf("found %d devices for cluster %s, expected %d",
+			len(devices), cluster.ID, expectedNodes)
+	}
+
+	facilities := map[string]bool{}
+	plans := map[string]bool{}
+	for _, device := range devices {
+		if device.State != "active" {
+			return errors.Errorf("device %s is %s, expected active", device.Hostname, device.State)
+		}
+		facilities[device.Facility.Code] = true
+		plans[device.Plan.Slug] = true
+	}
+	if len(facilities) != 1 {
+		return errors.Errorf("devices for cluster %s span multiple facilities: %v", cluster.ID, keys(facilities))
+	}
+	if len(plans) != 1 {
+		return errors.Errorf("devices for cluster %s span multiple plans: %v", cluster.ID, keys(plans))
+	}
+
+	options, err := providers.LoadOptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	if options.Packet != nil && options.Packet.Facility != "" && !facilities[options.Packet.Facility] {
+		return errors.Errorf("devices for cluster %s are in facility %v, expected %s",
+			cluster.ID, keys(facilities), options.Packet.Facility)
+	}
+
+	return nil
+}
+
+// VerifyCleanedUp implements Verifier. It checks that no devices remain
+// for the cluster.
+func (v *packetVerifier) VerifyCleanedUp(clusterID string) error {
+	devices, err := v.devicesForCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if len(devices) != 0 {
+		hostnames := make([]string, 0, len(devices))
+		for _, device := range devices {
+			hostnames = append(hostnames, device.Hostname)
+		}
+		return errors.Errorf("devices still exist for cluster %s after cleanup: %s",
+			clusterID, strings.Join(hostnames, ", "))
+	}
+
+	return nil
+}
+
+// packetDevice is the subset of a device the verifier cares about.
+type packetDevice struct {
+	Hostname string   `json:"hostname"`
+	State    string   `json:"state"`
+	Tags     []string `json:"tags"`
+	Plan     struct {
+		Slug string `json:"slug"`
+	} `json:"plan"`
+	Facility struct {
+		Code string `json:"code"`
+	} `json:"facility"`
+}
+
+// devicesForCluster lists project devices tagged with the cluster ID.
+func (v *packetVerifier) devicesForCluster(clusterID string) ([]packetDevice, error) {
+	u := fmt.Sprintf("%s/projects/%s/devices?per_page=1000", packetBaseURL, v.projectID)
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "building request")
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("GET %s: unexpected status %d: %s", u, resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Devices []packetDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling device list")
+	}
+
+	var matched []packetDevice
+	for _, device := range result.Devices {
+		for _, tag := range device.Tags {
+			if strings.Contains(tag, clusterID) {
+				matched = append(matched, device)
+				break
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+func keys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	return out
+}
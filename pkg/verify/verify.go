@@ -0,0 +1,39 @@
+// Package verify checks provider-side (cloud infrastructure) state against
+// what the provision API claims, both after provisioning and after
+// cleanup. Verifiers are optional: a run without provider credentials
+// simply skips provider-side verification.
+package verify
+
+import "github.com/containership/e2e-test/pkg/cloud"
+
+// Verifier checks infrastructure-level state for one provider.
+type Verifier interface {
+	// Provider returns the provider name this verifier covers.
+	Provider() string
+	// VerifyProvisioned checks that the expected provider resources for
+	// the cluster exist and are tagged with the cluster ID.
+	VerifyProvisioned(cluster *cloud.Cluster, nodePools []cloud.NodePool) error
+	// VerifyCleanedUp checks that no provider resources remain for the
+	// cluster after deletion.
+	VerifyCleanedUp(clusterID string) error
+}
+
+// factories maps provider name to a constructor returning (nil, nil) when
+// the provider's credentials aren't configured in the environment.
+var factories = map[string]func() (Verifier, error){}
+
+// register adds a verifier factory for a provider.
+func register(provider string, factory func() (Verifier, error)) {
+	factories[provider] = factory
+}
+
+// ForProvider returns a verifier for the named provider, or nil if the
+// provider has no verifier or its credentials aren't configured.
+func ForProvider(name string) (Verifier, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return factory()
+}
@@ -0,0 +1,180 @@
+// Package versions resolves Kubernetes version specs against the versions
+// the Containership API reports as supported, so nightly jobs can track
+// "latest" without manual bumps.
+package versions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Resolve resolves a version spec to a concrete supported version:
+//
+//	"1.15.3"   -> itself, if supported
+//	"1.15.x"   -> the newest supported patch of the 1.15 stream
+//	"latest"   -> the newest supported version
+//	"latest-1" -> the newest patch of the previous minor stream
+func Resolve(spec string, supported []string) (string, error) {
+	if len(supported) == 0 {
+		return "", errors.New("no supported Kubernetes versions reported")
+	}
+
+	if strings.HasSuffix(spec, ".x") {
+		return resolveStream(strings.TrimSuffix(spec, ".x"), supported)
+	}
+
+	if !strings.HasPrefix(spec, "latest") {
+		for _, v := range supported {
+			if v == spec {
+				return v, nil
+			}
+		}
+		return "", errors.Errorf("version %s is not supported (supported: %s)",
+			spec, strings.Join(supported, ", "))
+	}
+
+	back := 0
+	if spec != "latest" {
+		var err error
+		back, err = parseLatestOffset(spec)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	streams, err := minorStreams(supported)
+	if err != nil {
+		return "", err
+	}
+	if back >= len(streams) {
+		return "", errors.Errorf("%s requested but only %d minor streams are supported", spec, len(streams))
+	}
+
+	return streams[back], nil
+}
+
+// ResolveMatrix resolves a comma-separated version spec ("1.14.x,1.15.x",
+// "latest-1,latest") into concrete supported versions, preserving order
+// and dropping duplicates, so one CI job can cover a whole version matrix.
+func ResolveMatrix(spec string, supported []string) ([]string, error) {
+	seen := map[string]bool{}
+	var resolved []string
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		version, err := Resolve(item, supported)
+		if err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		resolved = append(resolved, version)
+	}
+
+	if len(resolved) == 0 {
+		return nil, errors.Errorf("version spec %q resolves to no versions", spec)
+	}
+
+	return resolved, nil
+}
+
+// resolveStream resolves an "N.M.x" wildcard to the newest supported patch
+// of that minor stream.
+func resolveStream(stream string, supported []string) (string, error) {
+	streams, err := minorStreams(supported)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range streams {
+		if strings.HasPrefix(v, stream+".") {
+			return v, nil
+		}
+	}
+
+	return "", errors.Errorf("no supported version in the %s stream (supported: %s)",
+		stream, strings.Join(supported, ", "))
+}
+
+// UpgradePath generates the valid upgrade path ending at the newest
+// supported version: the newest patch of each of the three newest minor
+// streams, oldest first (n-2 → n-1 → n). Fewer streams yield a shorter
+// path.
+func UpgradePath(supported []string) ([]string, error) {
+	streams, err := minorStreams(supported)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(streams) > 3 {
+		streams = streams[:3]
+	}
+
+	// streams is newest-first; an upgrade path runs oldest-first.
+	path := make([]string, len(streams))
+	for i, v := range streams {
+		path[len(streams)-1-i] = v
+	}
+
+	return path, nil
+}
+
+// parseLatestOffset parses "latest-N" into N.
+func parseLatestOffset(spec string) (int, error) {
+	suffix := strings.TrimPrefix(spec, "latest-")
+	back, err := strconv.Atoi(suffix)
+	if err != nil || back < 1 {
+		return 0, errors.Errorf("invalid version spec %q (expected latest or latest-N)", spec)
+	}
+
+	return back, nil
+}
+
+// minorStreams returns the newest patch release of each minor stream,
+// newest stream first.
+func minorStreams(supported []string) ([]string, error) {
+	type version struct {
+		major, minor, patch int
+	}
+
+	newest := map[string]version{}
+	for _, s := range supported {
+		var v version
+		if _, err := fmt.Sscanf(s, "%d.%d.%d", &v.major, &v.minor, &v.patch); err != nil {
+			return nil, errors.Errorf("unparseable supported version %q", s)
+		}
+
+		stream := fmt.Sprintf("%d.%d", v.major, v.minor)
+		if current, ok := newest[stream]; !ok || v.patch > current.patch {
+			newest[stream] = v
+		}
+	}
+
+	versions := make([]version, 0, len(newest))
+	for _, v := range newest {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].major != versions[j].major {
+			return versions[i].major > versions[j].major
+		}
+		return versions[i].minor > versions[j].minor
+	})
+
+	out := make([]string, len(versions))
+	for i, v := range versions {
+		out[i] = fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	}
+
+	return out, nil
+}
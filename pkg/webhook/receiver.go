@@ -0,0 +1,139 @@
+// Package webhook runs a small HTTP receiver that records webhook
+// deliveries from the platform, for asserting that lifecycle events
+// actually fire. The receiver listens locally; CI is responsible for
+// mapping a publicly reachable URL (an ngrok-style tunnel or ingress) onto
+// the listen address and exporting it via E2E_WEBHOOK_PUBLIC_URL.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/constants"
+)
+
+// defaultListenAddr is used when E2E_WEBHOOK_LISTEN_ADDR is unset.
+const defaultListenAddr = ":8478"
+
+// Delivery is one recorded webhook delivery.
+type Delivery struct {
+	// Type and ResourceID are parsed from the payload when present.
+	Type       string `json:"type,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	// Body is the raw payload for debugging unexpected shapes.
+	Body       json.RawMessage `json:"body"`
+	ReceivedAt time.Time       `json:"received_at"`
+}
+
+// Receiver records webhook deliveries until stopped.
+type Receiver struct {
+	server *http.Server
+
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// PublicURL returns the externally reachable URL for the receiver, or ""
+// if the environment doesn't provide one (in which case webhook suites
+// should skip).
+func PublicURL() string {
+	return os.Getenv(constants.WebhookPublicURLEnvVar)
+}
+
+// StartReceiver starts the receiver on the configured listen address.
+func StartReceiver() (*Receiver, error) {
+	addr := os.Getenv(constants.WebhookListenAddrEnvVar)
+	if addr == "" {
+		addr = defaultListenAddr
+	}
+
+	receiver := &Receiver{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", receiver.handle)
+	receiver.server = &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- receiver.server.ListenAndServe()
+	}()
+
+	// Surface immediate bind failures instead of timing out later.
+	select {
+	case err := <-errCh:
+		return nil, errors.Wrapf(err, "listening on %s", addr)
+	case <-time.After(100 * time.Millisecond):
+		return receiver, nil
+	}
+}
+
+// Stop shuts the receiver down.
+func (r *Receiver) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.server.Shutdown(ctx)
+}
+
+// handle records a delivery.
+func (r *Receiver) handle(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	delivery := Delivery{
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+	// Best-effort parse of the standard payload shape; unknown shapes are
+	// still recorded raw.
+	var payload struct {
+		Type       string `json:"type"`
+		ResourceID string `json:"resource_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		delivery.Type = payload.Type
+		delivery.ResourceID = payload.ResourceID
+	}
+
+	r.mu.Lock()
+	r.deliveries = append(r.deliveries, delivery)
+	r.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries returns a snapshot of everything received so far.
+func (r *Receiver) Deliveries() []Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Delivery(nil), r.deliveries...)
+}
+
+// WaitFor blocks until a delivery matching the type and resource ID
+// arrives or the timeout elapses.
+func (r *Receiver) WaitFor(eventType, resourceID string, timeout time.Duration) (*Delivery, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		for _, delivery := range r.Deliveries() {
+			if delivery.Type == eventType && delivery.ResourceID == resourceID {
+				d := delivery
+				return &d, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("no %s delivery for %s within %s", eventType, resourceID, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,108 @@
+package admission
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The admission suite deploys a validating webhook into the cluster and drives
+// admission requests through it. The apiserver-to-pod network path it
+// exercises differs per provider and has broken without any spec noticing.
+func TestAdmission(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("admission suite installs webhooks and workloads; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("admission")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("admission")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("admission")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Admission Suite", reporting.Reporters("admission"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	Expect(testcontext.Bootstrap("admission")).To(Succeed())
+
+	return nil
+}, func(data []byte) {
+	Expect(testcontext.EnsureBootstrapped("admission")).To(Succeed())
+})
+
+// Best-effort teardown: a leftover failurePolicy=Fail webhook would block
+// unrelated workloads, so always try to remove it even after a mid-spec
+// failure.
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	//nolint:errcheck - the cleanup spec already covers delete failures
+	testcontext.KubeClient().AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(
+		testcontext.Context(), webhookConfigName, metav1.DeleteOptions{})
+	//nolint:errcheck
+	testcontext.KubeClient().CoreV1().Namespaces().Delete(
+		testcontext.Context(), admissionNamespace, metav1.DeleteOptions{})
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
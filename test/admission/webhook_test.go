@@ -0,0 +1,304 @@
+package admission
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// These specs deploy the upstream agnhost sample webhook behind a Service
+// and register it as a validating webhook with failurePolicy=Fail, so a
+// successful round trip proves the API server can reach pods in-cluster.
+const (
+	admissionNamespace = "e2e-admission"
+	webhookConfigName  = "e2e-admission-webhook"
+	webhookService     = "e2e-webhook"
+	webhookDeployment  = "e2e-webhook"
+	webhookSecret      = "e2e-webhook-certs"
+	webhookPort        = 8443
+
+	// agnhost's webhook subcommand denies configmaps carrying this marker
+	// data, giving the specs a deterministic deny path to assert on.
+	disallowKey   = "webhook-e2e-test"
+	disallowValue = "webhook-disallow"
+
+	webhookImage = "k8s.gcr.io/e2e-test-images/agnhost:2.21"
+
+	webhookReadyTimeout = 5 * time.Minute
+	admissionTimeout    = 2 * time.Minute
+	admissionPoll       = 5 * time.Second
+)
+
+// caBundle carries the generated CA between the deploy and round-trip
+// specs, which must run in order.
+var caBundle []byte
+
+// selfSignedCerts generates a throwaway CA plus a server certificate for
+// the webhook Service's in-cluster DNS name, returning PEM-encoded CA cert,
+// server cert, and server key.
+func selfSignedCerts() (caPEM, certPEM, keyPEM []byte, err error) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "e2e-admission-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	serviceDNS := fmt.Sprintf("%s.%s.svc", webhookService, admissionNamespace)
+	serverTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: serviceDNS},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{serviceDNS},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, &serverTemplate, &caTemplate, &serverKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encode := func(blockType string, der []byte) []byte {
+		var buf bytes.Buffer
+		//nolint:errcheck - writes to a bytes.Buffer cannot fail
+		pem.Encode(&buf, &pem.Block{Type: blockType, Bytes: der})
+		return buf.Bytes()
+	}
+
+	return encode("CERTIFICATE", caDER),
+		encode("CERTIFICATE", serverDER),
+		encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(serverKey)),
+		nil
+}
+
+// webhookWorkload returns the agnhost webhook deployment serving TLS with
+// the certs mounted from the webhook secret.
+func webhookWorkload() *appsv1.Deployment {
+	replicas := int32(1)
+	labels := map[string]string{"app": webhookDeployment}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   webhookDeployment,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "webhook",
+							Image: webhookImage,
+							Args: []string{
+								"webhook",
+								fmt.Sprintf("--tls-cert-file=%s", "/certs/tls.crt"),
+								fmt.Sprintf("--tls-private-key-file=%s", "/certs/tls.key"),
+								fmt.Sprintf("--port=%d", webhookPort),
+							},
+							Ports: []corev1.ContainerPort{
+								{ContainerPort: webhookPort},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "certs", MountPath: "/certs", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "certs",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: webhookSecret},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// webhookConfig returns a validating webhook registration for configmap
+// creates, scoped to the test namespace so a broken webhook can't block
+// anything else in the cluster.
+func webhookConfig(caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	failurePolicy := admissionregistrationv1.Fail
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	path := "/configmaps"
+	port := int32(443)
+
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: webhookConfigName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "deny-marked-configmaps.e2e.containership.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: admissionNamespace,
+						Name:      webhookService,
+						Path:      &path,
+						Port:      &port,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregistrationv1.RuleWithOperations{
+					{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"configmaps"},
+						},
+					},
+				},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"e2e.containership.io/admission-test": "true"},
+				},
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+		},
+	}
+}
+
+var _ = Describe("Validating admission webhook", func() {
+	It("should deploy the webhook server behind a service", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("generating self-signed certs for the webhook service DNS name")
+		caPEM, certPEM, keyPEM, err := selfSignedCerts()
+		Expect(err).NotTo(HaveOccurred())
+		caBundle = caPEM
+
+		log.Step("creating the labeled test namespace")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   admissionNamespace,
+				Labels: map[string]string{"e2e.containership.io/admission-test": "true"},
+			},
+		}
+		_, err = kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		log.Step("creating the cert secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: webhookSecret},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": certPEM,
+				"tls.key": keyPEM,
+			},
+		}
+		_, err = kube.CoreV1().Secrets(admissionNamespace).Create(testcontext.Context(), secret, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("deploying the webhook server and its service")
+		_, err = kube.AppsV1().Deployments(admissionNamespace).Create(testcontext.Context(), webhookWorkload(), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: webhookService},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": webhookDeployment},
+				Ports: []corev1.ServicePort{
+					{Port: 443, TargetPort: intstr.FromInt(webhookPort)},
+				},
+			},
+		}
+		_, err = kube.CoreV1().Services(admissionNamespace).Create(testcontext.Context(), service, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the webhook server to be ready")
+		Eventually(func() (int32, error) {
+			deployment, err := kube.AppsV1().Deployments(admissionNamespace).Get(testcontext.Context(), webhookDeployment, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, webhookReadyTimeout, admissionPoll).Should(Equal(int32(1)))
+	})
+
+	It("should complete an admission round trip through the webhook", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("registering the validating webhook")
+		_, err := kube.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(
+			testcontext.Context(), webhookConfig(caBundle), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The apiserver loads new webhook configurations asynchronously, so
+		// poll the deny path until the webhook demonstrably intercepts.
+		log.Step("waiting for the webhook to start denying marked configmaps")
+		Eventually(func() bool {
+			denied := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "denied-"},
+				Data:       map[string]string{disallowKey: disallowValue},
+			}
+			_, err := kube.CoreV1().ConfigMaps(admissionNamespace).Create(testcontext.Context(), denied, metav1.CreateOptions{})
+			return err != nil && !apierrors.IsAlreadyExists(err)
+		}, admissionTimeout, admissionPoll).Should(BeTrue(),
+			"marked configmaps were never denied; the apiserver cannot reach the webhook pod")
+
+		log.Step("verifying unmarked configmaps are still admitted")
+		allowed := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: "allowed-"},
+			Data:       map[string]string{"unrelated": "data"},
+		}
+		_, err = kube.CoreV1().ConfigMaps(admissionNamespace).Create(testcontext.Context(), allowed, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred(),
+			"the webhook denied an unmarked configmap; admission responses are not round-tripping correctly")
+	})
+
+	It("should clean up the webhook and its workload", func() {
+		kube := testcontext.KubeClient()
+
+		err := kube.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(
+			testcontext.Context(), webhookConfigName, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = kube.CoreV1().Namespaces().Delete(testcontext.Context(), admissionNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
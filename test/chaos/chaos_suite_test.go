@@ -0,0 +1,183 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/templates"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const templatePath = "../../templates/digitalocean.yaml"
+
+// brokenUserData is a bootstrap fragment that fails immediately, so the
+// worker pool can never come up. The suite verifies the provision API turns
+// this into a clear error state instead of hanging until the timeout.
+const brokenUserData = "#!/bin/sh\n# e2e bootstrap failure injection\nexit 1\n"
+
+// The chaos suite provisions a cluster whose worker pool has a deliberately
+// broken bootstrap and verifies failure reporting, then tears it down.
+func TestChaos(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("chaos suite mutates org state; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("chaos")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("chaos")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("chaos")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Chaos Suite", reporting.Reporters("chaos"))
+}
+
+// injectBrokenBootstrap returns the template configuration with the worker
+// pool's user data replaced by a fragment that fails immediately.
+func injectBrokenBootstrap(configuration json.RawMessage) (json.RawMessage, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(configuration, &config); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling template configuration")
+	}
+
+	resource, ok := config["resource"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("template configuration has no resource block")
+	}
+
+	for _, pools := range resource {
+		poolMap, ok := pools.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		workerPool, ok := poolMap["worker_pool"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		workerPool["user_data"] = brokenUserData
+
+		broken, err := json.Marshal(config)
+		return broken, errors.Wrap(err, "marshaling broken configuration")
+	}
+
+	return nil, errors.New("template configuration has no worker_pool to break")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	rendered, err := templates.Render(templatePath, templates.DefaultVars(constants.DefaultKubernetesVersion))
+	Expect(err).NotTo(HaveOccurred())
+	templateReq := rendered.Request
+
+	templateReq.Configuration, err = injectBrokenBootstrap(templateReq.Configuration)
+	Expect(err).NotTo(HaveOccurred())
+	templateReq.Description = "e2e chaos template (broken worker bootstrap)"
+
+	//nolint:errcheck - artifact export is best-effort
+	artifacts.WriteJSON("chaos-template-request.json", templateReq)
+
+	template, err := client.CreateTemplate(templateReq)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetTemplateID(template.ID)
+
+	cluster, err := client.CreateCluster(&cloud.CreateClusterRequest{
+		TemplateID: template.ID,
+		Name:       fmt.Sprintf("e2e-chaos-%d", time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetClusterID(cluster.ID)
+
+	return []byte(fmt.Sprintf("%s,%s", cluster.ID, template.ID))
+}, func(data []byte) {
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(2))
+
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+	}
+	testcontext.SetClusterID(parts[0])
+	testcontext.SetTemplateID(parts[1])
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	client := testcontext.CloudClient()
+
+	// The broken cluster is disposable; tear it down regardless of how the
+	// specs went.
+	Expect(client.DeleteCluster(testcontext.ClusterID())).To(Succeed())
+	Expect(client.WaitForClusterDeleted(testcontext.ClusterID(), constants.DeleteTimeout)).To(Succeed())
+	Expect(client.DeleteTemplate(testcontext.TemplateID())).To(Succeed())
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,28 @@
+package chaos
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var _ = Describe("Bootstrap failure reporting", func() {
+	It("should surface a clear error state within the bounded time", func() {
+		status, err := testcontext.CloudClient().WaitForClusterErrorState(
+			testcontext.ClusterID(), constants.ErrorSurfaceTimeout)
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Infof("cluster surfaced error state: %s", status.Message)
+		//nolint:errcheck - artifact export is best-effort
+		artifacts.WriteJSON("chaos-error-status.json", status)
+
+		// An error state without a message is as useless to a user as a
+		// hang; require the API to say what went wrong.
+		Expect(status.Message).NotTo(BeEmpty(),
+			"error state should carry a message explaining the failure")
+	})
+})
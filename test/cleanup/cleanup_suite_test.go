@@ -0,0 +1,129 @@
+package cleanup
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/lock"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/timeline"
+)
+
+// statePoller samples cluster and node pool statuses in the background for
+// the whole run; the resulting timeline is written out at suite teardown.
+var statePoller *timeline.Poller
+
+func TestCleanup(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("cleanup suite mutates org state; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("cleanup")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("cleanup")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("cleanup")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Cleanup Suite", reporting.Reporters("cleanup"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the cleanup suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+	testcontext.SetTemplateID(os.Getenv(constants.TemplateIDEnvVar))
+
+	statePoller = timeline.NewPoller(client, clusterID, constants.TimelinePollInterval)
+	statePoller.Start()
+
+	return nil
+}, func(_ []byte) {
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+		testcontext.SetClusterID(testcontext.ResolveClusterID())
+		testcontext.SetTemplateID(os.Getenv(constants.TemplateIDEnvVar))
+	}
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	if statePoller != nil {
+		statePoller.Stop()
+		err := statePoller.WriteArtifact()
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// The run is done with the shared org; let the next pipeline in.
+	if orgLock := lock.FromEnv(); orgLock != nil {
+		err := orgLock.Release()
+		Expect(err).NotTo(HaveOccurred())
+	}
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,132 @@
+package cleanup
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/integrity"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/util"
+	"github.com/containership/e2e-test/pkg/verify"
+)
+
+var _ = Describe("Cleaning up a cluster", func() {
+	// Stale finalizers have wedged deletes before; catch them while the
+	// cluster is still reachable rather than debugging a hung DELETE.
+	It("should have no stale containership.io metadata before deletion", func() {
+		kubeClient, err := k8s.New(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		problems, err := integrity.Audit(kubeClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		if len(problems) > 0 {
+			//nolint:errcheck - artifact export is best-effort
+			artifacts.WriteJSON("integrity-problems.json", problems)
+		}
+		Expect(problems).To(BeEmpty(), "containership.io metadata audit found problems")
+	})
+
+	It("should delete the cluster", func() {
+		err := util.RetryCloudCall(func() error {
+			return testcontext.CloudClient().DeleteCluster(testcontext.ClusterID())
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should observe the cluster fully deleted", func() {
+		// Track per-pool node teardown alongside the cluster state so a
+		// stuck delete is localized to the pool that never emptied.
+		report, err := testcontext.CloudClient().WaitForClusterTeardown(testcontext.ClusterID(), config.DeleteTimeout())
+		if report != nil {
+			//nolint:errcheck - artifact export is best-effort
+			artifacts.WriteJSON("teardown-report.json", report)
+		}
+		if err != nil {
+			// The normal delete timed out; escalate to a force-delete
+			// before giving up so the shared org isn't left holding a
+			// half-deleted cluster.
+			log.Warnf("delete timed out, escalating: %v", err)
+			Expect(testcontext.CloudClient().EscalateStuckDelete(testcontext.ClusterID())).To(Succeed())
+			Fail(fmt.Sprintf("cluster required force-delete escalation: %v", err))
+		}
+	})
+
+	It("should emit an audit event for the cluster deletion", func() {
+		// The delete event may land shortly after the cluster disappears.
+		var event *cloud.Event
+		Eventually(func() (bool, error) {
+			events, err := testcontext.CloudClient().ListEvents()
+			if cloud.IsNotFound(err) {
+				Skip("events API not available in this environment")
+			}
+			if err != nil {
+				return false, err
+			}
+			event = cloud.FindEvent(events, cloud.EventClusterDeleted, testcontext.ClusterID())
+			return event != nil, nil
+		}, 2*time.Minute, constants.DefaultPollInterval).Should(BeTrue(),
+			"no %s event for cluster %s", cloud.EventClusterDeleted, testcontext.ClusterID())
+
+		Expect(event.ActorID).NotTo(BeEmpty(), "audit event is not attributable to an actor")
+	})
+
+	It("should delete SSH keys uploaded by e2e runs", func() {
+		deleted, err := testcontext.CloudClient().DeleteOwnedSSHKeys()
+		Expect(err).NotTo(HaveOccurred())
+		for _, name := range deleted {
+			log.Infof("deleted leftover SSH key %s", name)
+		}
+	})
+
+	It("should leave no provider-side resources behind", func() {
+		if testcontext.TemplateID() == "" {
+			Skip("template ID unknown; cannot determine provider")
+		}
+
+		template, err := testcontext.CloudClient().GetTemplate(testcontext.TemplateID())
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier, err := verify.ForProvider(template.ProviderName)
+		Expect(err).NotTo(HaveOccurred())
+		if verifier == nil {
+			Skip(fmt.Sprintf("no provider verifier configured for %s", template.ProviderName))
+		}
+
+		Expect(verifier.VerifyCleanedUp(testcontext.ClusterID())).To(Succeed())
+	})
+
+	// The provision suite's template used to be left behind forever, so the
+	// shared org accumulated one template per run. This must stay after the
+	// provider verification spec, which still needs to read the template.
+	It("should delete the template", func() {
+		if testcontext.TemplateID() == "" {
+			Skip("template ID unknown; nothing to delete")
+		}
+
+		err := util.RetryCloudCall(func() error {
+			return testcontext.CloudClient().DeleteTemplate(testcontext.TemplateID())
+		})
+		if cloud.IsNotFound(err) {
+			// Already gone (an operator or an earlier attempt beat us to
+			// it) — that's the state we want.
+			err = nil
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() bool {
+			_, err := testcontext.CloudClient().GetTemplate(testcontext.TemplateID())
+			return cloud.IsNotFound(err)
+		}, 2*time.Minute, constants.DefaultPollInterval).Should(BeTrue(),
+			"template %s still exists after deletion", testcontext.TemplateID())
+	})
+})
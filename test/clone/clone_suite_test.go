@@ -0,0 +1,144 @@
+package clone
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The clone suite covers the product's "clone this cluster" workflow: it
+// provisions a second cluster from the template of the existing cluster
+// (CONTAINERSHIP_CLUSTER_ID) and verifies the two are equivalent.
+var (
+	sourceClusterID string
+	cloneClusterID  string
+)
+
+func TestClone(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("clone suite mutates org state; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("clone")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("clone")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("clone")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Clone Suite", reporting.Reporters("clone"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	sourceClusterID = testcontext.ResolveClusterID()
+	Expect(sourceClusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the clone suite", constants.ClusterIDEnvVar)
+
+	source, err := client.GetCluster(sourceClusterID)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(source.TemplateID).NotTo(BeEmpty(), "source cluster %s has no template", sourceClusterID)
+
+	clone, err := client.CreateCluster(&cloud.CreateClusterRequest{
+		TemplateID: source.TemplateID,
+		Name:       fmt.Sprintf("e2e-clone-%d", time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	cloneClusterID = clone.ID
+
+	err = client.WaitForClusterRunning(clone.ID, constants.ProvisionTimeout)
+	Expect(err).NotTo(HaveOccurred())
+
+	return []byte(fmt.Sprintf("%s,%s", sourceClusterID, clone.ID))
+}, func(data []byte) {
+	// Non-primary nodes only need the IDs and their own client.
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(2))
+
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+	}
+	sourceClusterID = parts[0]
+	cloneClusterID = parts[1]
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	if cloneClusterID == "" {
+		return
+	}
+
+	client := testcontext.CloudClient()
+	err := client.DeleteCluster(cloneClusterID)
+	Expect(err).NotTo(HaveOccurred())
+	err = client.WaitForClusterDeleted(cloneClusterID, constants.DeleteTimeout)
+	Expect(err).NotTo(HaveOccurred())
+})
+
+// On any spec failure, snapshot the cloud-side state of the clone into the
+// artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed && testcontext.CloudClient() != nil {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		testcontext.CloudClient().DumpState(cloneClusterID, "")
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
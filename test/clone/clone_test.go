@@ -0,0 +1,52 @@
+package clone
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var _ = Describe("Cloning a cluster from its template", func() {
+	It("should result in a running clone", func() {
+		clone, err := testcontext.CloudClient().GetCluster(cloneClusterID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clone.Status.Type).To(Equal(cloud.ClusterStatusRunning))
+	})
+
+	It("should report the same template and Kubernetes version as the source", func() {
+		source, err := testcontext.CloudClient().GetCluster(sourceClusterID)
+		Expect(err).NotTo(HaveOccurred())
+		clone, err := testcontext.CloudClient().GetCluster(cloneClusterID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(clone.TemplateID).To(Equal(source.TemplateID))
+		Expect(clone.KubernetesVersion).To(Equal(source.KubernetesVersion))
+	})
+
+	It("should have node pools equivalent to the source", func() {
+		sourcePools, err := testcontext.CloudClient().ListNodePools(sourceClusterID)
+		Expect(err).NotTo(HaveOccurred())
+		clonePools, err := testcontext.CloudClient().ListNodePools(cloneClusterID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(clonePools).To(HaveLen(len(sourcePools)))
+
+		// Pools have fresh IDs on the clone; compare by shape.
+		type shape struct {
+			mode    string
+			version string
+			count   int32
+		}
+		shapes := func(pools []cloud.NodePool) map[shape]int {
+			out := map[shape]int{}
+			for _, pool := range pools {
+				out[shape{pool.KubernetesMode, pool.KubernetesVersion, pool.Count}]++
+			}
+			return out
+		}
+
+		Expect(shapes(clonePools)).To(Equal(shapes(sourcePools)))
+	})
+})
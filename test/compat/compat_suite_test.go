@@ -0,0 +1,84 @@
+package compat
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The compat suite replays the critical read flows through the wire types
+// pinned in pkg/compat — the shapes an earlier release of the harness and
+// its CLI/clientset generation were built against — proving the current
+// backend still serves existing clients. It complements the contract
+// suite, which tracks the live types: contract catches drift going
+// forward, compat catches breaks looking back. It is optional (pass
+// --compat to run it), non-mutating, and safe against production.
+func TestCompat(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("compat")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("compat")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("compat")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Compat Suite", reporting.Reporters("compat"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	Expect(testcontext.Bootstrap("compat")).To(Succeed())
+
+	return nil
+}, func(_ []byte) {
+	Expect(testcontext.EnsureBootstrapped("compat")).To(Succeed())
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,82 @@
+package compat
+
+import (
+	"flag"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/compat"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The compat checks are an optional job mode: they only add signal around
+// backend releases, so routine pipelines skip them.
+var compatMode = flag.Bool("compat", false,
+	"run the backward-compatibility specs against the pinned previous client types")
+
+func skipUnlessCompatMode() {
+	if !*compatMode {
+		Skip("backward-compatibility specs are opt-in; pass --compat to run them")
+	}
+}
+
+var _ = Describe("Backend backward compatibility", func() {
+	It("should serve clusters to previous-release clients", func() {
+		skipUnlessCompatMode()
+
+		raw, err := testcontext.CloudClient().GetClusterRaw(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster, err := compat.DecodeCluster(raw)
+		Expect(err).NotTo(HaveOccurred(),
+			"the backend no longer serves clusters parseable by the pinned client")
+		log.Infof("pinned client sees cluster %s (%s, status %s)",
+			cluster.ID, cluster.Name, cluster.Status.Type)
+	})
+
+	It("should serve node pools to previous-release clients", func() {
+		skipUnlessCompatMode()
+		client := testcontext.CloudClient()
+
+		pools, err := client.ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pools).NotTo(BeEmpty(), "cluster %s has no node pools", testcontext.ClusterID())
+
+		for _, pool := range pools {
+			raw, err := client.GetNodePoolRaw(testcontext.ClusterID(), pool.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			decoded, err := compat.DecodeNodePool(raw)
+			Expect(err).NotTo(HaveOccurred(),
+				"the backend no longer serves node pool %s parseable by the pinned client", pool.ID)
+			Expect(decoded.Count).To(Equal(pool.Count))
+		}
+	})
+
+	It("should serve templates to previous-release clients", func() {
+		skipUnlessCompatMode()
+		client := testcontext.CloudClient()
+
+		cluster, err := client.GetCluster(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		templateID := cluster.TemplateID
+		if templateID == "" {
+			templates, err := client.ListTemplates()
+			Expect(err).NotTo(HaveOccurred())
+			if len(templates) == 0 {
+				Skip(fmt.Sprintf("cluster %s has no template and the org has none to check against", testcontext.ClusterID()))
+			}
+			templateID = templates[0].ID
+		}
+
+		raw, err := client.GetTemplateRaw(templateID)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = compat.DecodeTemplate(raw)
+		Expect(err).NotTo(HaveOccurred(),
+			"the backend no longer serves templates parseable by the pinned client")
+	})
+})
@@ -0,0 +1,75 @@
+package contract
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// verifyContract strict-decodes a raw API object into the given client
+// type and fails the spec on unknown or missing fields.
+func verifyContract(kind string, raw []byte, out interface{}) {
+	log.Step("strict-decoding the %s response (%d bytes)", kind, len(raw))
+	Expect(cloud.StrictDecode(raw, out)).To(Succeed(),
+		"the provision API sends %s fields the client types don't declare; update pkg/cloud", kind)
+
+	missing, err := cloud.MissingFields(raw, out)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(missing).To(BeEmpty(),
+		"the provision API stopped sending %s fields the client types expect: %v", kind, missing)
+}
+
+var _ = Describe("Provision API contract", func() {
+	It("should match the client's cluster type", func() {
+		raw, err := testcontext.CloudClient().GetClusterRaw(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		var cluster cloud.Cluster
+		verifyContract("cluster", raw, &cluster)
+		Expect(cluster.ID).To(Equal(testcontext.ClusterID()))
+	})
+
+	It("should match the client's node pool type", func() {
+		client := testcontext.CloudClient()
+		pools, err := client.ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pools).NotTo(BeEmpty(), "cluster %s has no node pools", testcontext.ClusterID())
+
+		raw, err := client.GetNodePoolRaw(testcontext.ClusterID(), pools[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		var pool cloud.NodePool
+		verifyContract("node pool", raw, &pool)
+		Expect(pool.ID).To(Equal(pools[0].ID))
+	})
+
+	It("should match the client's template type", func() {
+		client := testcontext.CloudClient()
+
+		cluster, err := client.GetCluster(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		templateID := cluster.TemplateID
+		if templateID == "" {
+			// Fall back to any template in the org; the shape is what's
+			// under test, not the particular object.
+			templates, err := client.ListTemplates()
+			Expect(err).NotTo(HaveOccurred())
+			if len(templates) == 0 {
+				Skip(fmt.Sprintf("cluster %s has no template and the org has none to check against", testcontext.ClusterID()))
+			}
+			templateID = templates[0].ID
+		}
+
+		raw, err := client.GetTemplateRaw(templateID)
+		Expect(err).NotTo(HaveOccurred())
+
+		var template cloud.Template
+		verifyContract("template", raw, &template)
+		Expect(template.ID).To(Equal(templateID))
+	})
+})
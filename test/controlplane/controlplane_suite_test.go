@@ -0,0 +1,115 @@
+package controlplane
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The control plane suite restarts the apiserver, controller-manager, and
+// scheduler pods one component at a time and verifies the cluster stays
+// functional throughout, guarding the HA story of our control-plane layout.
+func TestControlPlane(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("control plane suite restarts system pods; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("controlplane")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("controlplane")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("controlplane")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Control Plane Suite", reporting.Reporters("controlplane"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the control plane suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+
+	kubeClient, err := k8s.New(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetKubeClient(kubeClient)
+
+	return nil
+}, func(data []byte) {
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+		testcontext.SetClusterID(testcontext.ResolveClusterID())
+
+		kubeClient, err := k8s.New(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetKubeClient(kubeClient)
+	}
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
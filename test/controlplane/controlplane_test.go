@@ -0,0 +1,187 @@
+package controlplane
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	systemNamespace = "kube-system"
+	probeNamespace  = "e2e-controlplane"
+	probeDeployment = "probe"
+
+	// restartTimeout bounds how long a restarted component pod may take to
+	// come back Running.
+	restartTimeout = 5 * time.Minute
+	// probeTimeout bounds the functional probe after each restart.
+	probeTimeout = 5 * time.Minute
+	statusPoll   = 10 * time.Second
+)
+
+// componentPods lists the control plane pods for a component, selected by
+// the component label kubeadm-style static pods carry.
+func componentPods(component string) ([]corev1.Pod, error) {
+	pods, err := testcontext.KubeClient().CoreV1().Pods(systemNamespace).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "component=" + component,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// allComponentPodsRunning reports whether the component has at least want
+// pods and all of them are Running.
+func allComponentPodsRunning(component string, want int) (bool, error) {
+	pods, err := componentPods(component)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) < want {
+		return false, nil
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// restartComponent deletes the component's pods one at a time, waiting for
+// the component to be fully Running again between deletions so the cluster
+// never loses more than one instance at once.
+func restartComponent(component string) {
+	kube := testcontext.KubeClient()
+
+	pods, err := componentPods(component)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(pods).NotTo(BeEmpty(), "no %s pods found in %s", component, systemNamespace)
+	count := len(pods)
+
+	for _, pod := range pods {
+		log.Step("restarting %s on %s", pod.Name, pod.Spec.NodeName)
+		err := kube.CoreV1().Pods(systemNamespace).Delete(testcontext.Context(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Eventually(func() (bool, error) {
+			return allComponentPodsRunning(component, count)
+		}, restartTimeout, statusPoll).Should(BeTrue(),
+			"%s did not return to %d Running pods after restarting %s", component, count, pod.Name)
+	}
+}
+
+// verifyFunctional exercises the control plane end to end: the apiserver
+// answers reads and writes, the scheduler places a new pod, and the
+// controller-manager reconciles the deployment up to readiness.
+func verifyFunctional() {
+	kube := testcontext.KubeClient()
+
+	log.Step("verifying the API answers reads")
+	_, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	log.Step("verifying a new pod schedules and becomes ready")
+	ensureProbeDeployment()
+	// Touching the pod template forces a rollout, so readiness below proves
+	// the scheduler and controller-manager are live right now, not that an
+	// old replica is coasting.
+	deployment, err := kube.AppsV1().Deployments(probeNamespace).Get(
+		testcontext.Context(), probeDeployment, metav1.GetOptions{})
+	Expect(err).NotTo(HaveOccurred())
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations["e2e.containership.io/restarted-at"] = time.Now().Format(time.RFC3339)
+	_, err = kube.AppsV1().Deployments(probeNamespace).Update(testcontext.Context(), deployment, metav1.UpdateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	Eventually(func() (bool, error) {
+		deployment, err := kube.AppsV1().Deployments(probeNamespace).Get(
+			testcontext.Context(), probeDeployment, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return deployment.Status.ObservedGeneration >= deployment.Generation &&
+			deployment.Status.UpdatedReplicas == *deployment.Spec.Replicas &&
+			deployment.Status.ReadyReplicas == *deployment.Spec.Replicas, nil
+	}, probeTimeout, statusPoll).Should(BeTrue(),
+		"probe deployment did not roll out after the component restart")
+}
+
+// ensureProbeDeployment creates the probe namespace and deployment if they
+// don't exist yet.
+func ensureProbeDeployment() {
+	kube := testcontext.KubeClient()
+
+	_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: probeNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	labels := map[string]string{"app": probeDeployment}
+	replicas := int32(1)
+	_, err = kube.AppsV1().Deployments(probeNamespace).Create(testcontext.Context(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   probeDeployment,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "pause",
+							Image: "k8s.gcr.io/pause:3.2",
+						},
+					},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+var _ = Describe("Control plane component restarts", func() {
+	It("should stay functional while the apiserver restarts", func() {
+		restartComponent("kube-apiserver")
+		verifyFunctional()
+	})
+
+	It("should stay functional while the controller-manager restarts", func() {
+		restartComponent("kube-controller-manager")
+		verifyFunctional()
+	})
+
+	It("should stay functional while the scheduler restarts", func() {
+		restartComponent("kube-scheduler")
+		verifyFunctional()
+	})
+
+	It("should clean up the probe workload", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), probeNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,111 @@
+package helm
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	chartPath     = "testdata/chart"
+	helmNamespace = "e2e-helm"
+	releaseName   = "e2e-smoke"
+
+	installTimeout = 5 * time.Minute
+)
+
+// actionConfig builds a Helm action configuration that talks through the
+// proxy kubeconfig, the same way a customer running helm would.
+func actionConfig() (*action.Configuration, func(), error) {
+	kubeconfig, err := k8s.Kubeconfig(testcontext.ClusterID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.CreateTemp("", "e2e-helm-kubeconfig-*.yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.Write(kubeconfig); err != nil {
+		f.Close()
+		cleanup()
+		return nil, nil, err
+	}
+	f.Close()
+
+	kubeconfigPath := f.Name()
+	namespace := helmNamespace
+	flags := &genericclioptions.ConfigFlags{
+		KubeConfig: &kubeconfigPath,
+		Namespace:  &namespace,
+	}
+
+	config := new(action.Configuration)
+	logFn := func(format string, v ...interface{}) {
+		log.Debugf("helm: "+format, v...)
+	}
+	if err := config.Init(flags, namespace, "secret", logFn); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return config, cleanup, nil
+}
+
+var _ = Describe("Helm chart installation", func() {
+	It("should install the chart and reach deployed status", func() {
+		config, cleanup, err := actionConfig()
+		Expect(err).NotTo(HaveOccurred())
+		defer cleanup()
+
+		chart, err := loader.Load(chartPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		install := action.NewInstall(config)
+		install.ReleaseName = releaseName
+		install.Namespace = helmNamespace
+		install.CreateNamespace = true
+		// Wait for the chart's workloads to be ready, not just applied;
+		// that is where proxy streaming problems have surfaced before.
+		install.Wait = true
+		install.Timeout = installTimeout
+
+		rel, err := install.Run(chart, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rel.Info.Status).To(Equal(release.StatusDeployed),
+			"release %s is %s, expected deployed", releaseName, rel.Info.Status)
+
+		log.Step("verifying the chart's deployment is actually ready")
+		deployment, err := testcontext.KubeClient().AppsV1().Deployments(helmNamespace).Get(
+			testcontext.Context(), releaseName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Status.ReadyReplicas).To(BeNumerically(">", 0))
+	})
+
+	It("should uninstall the release cleanly", func() {
+		config, cleanup, err := actionConfig()
+		Expect(err).NotTo(HaveOccurred())
+		defer cleanup()
+
+		uninstall := action.NewUninstall(config)
+		_, err = uninstall.Run(releaseName)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), helmNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
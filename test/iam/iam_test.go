@@ -0,0 +1,70 @@
+package iam
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// revocationTimeout is how long key revocation may take to propagate
+	// before we consider it broken.
+	revocationTimeout = 2 * time.Minute
+	revocationPoll    = 5 * time.Second
+)
+
+// The specs below share one key's lifecycle, so they must run in order.
+var (
+	apiKey       *cloud.APIKey
+	scopedClient *cloud.Client
+)
+
+var _ = Describe("API key lifecycle", func() {
+	It("should create a scoped API key", func() {
+		key, err := testcontext.CloudClient().CreateAPIKey(&cloud.CreateAPIKeyRequest{
+			Name:  fmt.Sprintf("e2e-iam-%d", time.Now().Unix()),
+			Roles: []string{"read-only"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.Token).NotTo(BeEmpty(), "create response must include the key token")
+
+		apiKey = key
+		scopedClient = testcontext.CloudClient().WithToken(key.Token)
+	})
+
+	It("should authorize read operations with the scoped key", func() {
+		Expect(scopedClient).NotTo(BeNil(), "key creation must have succeeded")
+
+		_, err := scopedClient.ListClusters()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = scopedClient.ListSSHKeys()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should stop authorizing once the key is deleted", func() {
+		Expect(apiKey).NotTo(BeNil(), "key creation must have succeeded")
+		Expect(testcontext.CloudClient().DeleteAPIKey(apiKey.ID)).To(Succeed())
+
+		// Revocation may propagate asynchronously; require it to land
+		// within the bounded window.
+		Eventually(func() (bool, error) {
+			_, err := scopedClient.ListClusters()
+			if err == nil {
+				return false, nil
+			}
+
+			var apiErr *cloud.APIError
+			if !errors.As(err, &apiErr) {
+				return false, err
+			}
+			return apiErr.StatusCode == 401 || apiErr.StatusCode == 403, nil
+		}, revocationTimeout, revocationPoll).Should(BeTrue(),
+			"revoked key %s was still accepted after %s", apiKey.ID, revocationTimeout)
+	})
+})
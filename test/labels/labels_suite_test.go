@@ -0,0 +1,116 @@
+package labels
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// workerPoolID is the pool the suite sets custom labels on.
+var workerPoolID string
+
+// The labels suite exercises the label sync controller: it sets custom
+// labels on a worker pool through the API and verifies they appear on (and
+// disappear from) the pool's Kubernetes nodes.
+func TestLabels(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("labels suite mutates the cluster; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("labels")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("labels")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("labels")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Labels Suite", reporting.Reporters("labels"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the labels suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+
+	nodePools, err := client.ListNodePools(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	for i := range nodePools {
+		if !nodePools[i].IsMaster() {
+			workerPoolID = nodePools[i].ID
+			break
+		}
+	}
+	Expect(workerPoolID).NotTo(BeEmpty(), "cluster %s has no worker pools", clusterID)
+
+	kubeClient, err := k8s.New(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetKubeClient(kubeClient)
+
+	return []byte(workerPoolID)
+}, func(data []byte) {
+	Expect(testcontext.EnsureBootstrapped("labels")).To(Succeed())
+	workerPoolID = string(data)
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	// Clear any labels the specs left behind so repeated runs start clean.
+	client := testcontext.CloudClient()
+	Expect(client.SetNodePoolLabels(testcontext.ClusterID(), workerPoolID, map[string]string{})).To(Succeed())
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,129 @@
+package labels
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// testLabelKey is namespaced under the e2e domain so the suite can never
+	// collide with labels users or controllers care about.
+	testLabelKey   = "e2e.containership.io/labels-test"
+	testLabelValue = "set-by-e2e"
+
+	propagationTimeout = 5 * time.Minute
+	propagationPoll    = 10 * time.Second
+)
+
+// poolNodes lists the Kubernetes nodes belonging to the worker pool under
+// test, selected by the node pool ID label the node sync controller stamps.
+func poolNodes() ([]corev1.Node, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// allPoolNodesHaveLabel returns true once every node in the pool carries
+// the test label with the expected value.
+func allPoolNodesHaveLabel() (bool, error) {
+	nodes, err := poolNodes()
+	if err != nil {
+		return false, err
+	}
+	if len(nodes) == 0 {
+		return false, nil
+	}
+
+	for _, node := range nodes {
+		if node.Labels[testLabelKey] != testLabelValue {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// noPoolNodesHaveLabel returns true once no node in the pool carries the
+// test label.
+func noPoolNodesHaveLabel() (bool, error) {
+	nodes, err := poolNodes()
+	if err != nil {
+		return false, err
+	}
+
+	for _, node := range nodes {
+		if _, ok := node.Labels[testLabelKey]; ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+var _ = Describe("Node pool label propagation", func() {
+	It("should propagate pool labels to existing nodes", func() {
+		client := testcontext.CloudClient()
+		err := client.SetNodePoolLabels(testcontext.ClusterID(), workerPoolID, map[string]string{
+			testLabelKey: testLabelValue,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(allPoolNodesHaveLabel, propagationTimeout, propagationPoll).Should(BeTrue(),
+			"existing nodes in pool %s never received label %s", workerPoolID, testLabelKey)
+	})
+
+	It("should apply pool labels to nodes that join the pool", func() {
+		client := testcontext.CloudClient()
+
+		nodePool, err := client.GetNodePool(testcontext.ClusterID(), workerPoolID)
+		Expect(err).NotTo(HaveOccurred())
+		originalCount := nodePool.Count
+
+		log.Step("scaling the pool up by one node while the label is set")
+		scaleStart := time.Now()
+		Expect(client.ScaleNodePool(testcontext.ClusterID(), workerPoolID, originalCount+1)).To(Succeed())
+		Expect(client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)).To(Succeed())
+		defer func() {
+			log.Step("scaling the pool back down")
+			Expect(client.ScaleNodePool(testcontext.ClusterID(), workerPoolID, originalCount)).To(Succeed())
+			Expect(client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)).To(Succeed())
+		}()
+
+		log.Step("verifying the new node carries the label")
+		Eventually(func() (bool, error) {
+			nodes, err := poolNodes()
+			if err != nil {
+				return false, err
+			}
+			for _, node := range nodes {
+				if node.CreationTimestamp.Time.After(scaleStart) {
+					return node.Labels[testLabelKey] == testLabelValue, nil
+				}
+			}
+			return false, nil
+		}, propagationTimeout, propagationPoll).Should(BeTrue(),
+			"the node that joined pool %s never received label %s", workerPoolID, testLabelKey)
+	})
+
+	It("should remove pool labels from nodes when unset", func() {
+		client := testcontext.CloudClient()
+		err := client.SetNodePoolLabels(testcontext.ClusterID(), workerPoolID, map[string]string{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(noPoolNodesHaveLabel, propagationTimeout, propagationPoll).Should(BeTrue(),
+			"label %s was not removed from nodes in pool %s", testLabelKey, workerPoolID)
+	})
+})
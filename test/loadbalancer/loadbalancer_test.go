@@ -0,0 +1,214 @@
+package loadbalancer
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/providers"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// Cloud load balancer provisioning routinely takes several minutes and
+// varies widely by provider, so the deadline is a flag rather than a
+// constant.
+var lbTimeout = flag.Duration("lb-timeout", 10*time.Minute,
+	"how long to wait for the cloud load balancer to provision and to deprovision")
+
+const (
+	lbNamespace = "e2e-loadbalancer"
+	lbBackend   = "lb-backend"
+	lbService   = "lb-probe"
+	lbPort      = 8080
+	lbResponse  = "lb-probe-ok"
+	lbImage     = "docker.io/library/busybox:1.31.1"
+
+	lbPoll       = 10 * time.Second
+	fetchTimeout = 30 * time.Second
+)
+
+// Shared across the ordered specs below.
+var (
+	provider   string
+	lbEndpoint string
+)
+
+// supportsLoadBalancers lists the providers whose cloud controller manager
+// provisions Services of type LoadBalancer.
+var supportsLoadBalancers = map[string]bool{
+	providers.AmazonWebServices: true,
+	providers.Azure:             true,
+	providers.DigitalOcean:      true,
+	providers.Google:            true,
+}
+
+// fetchEndpoint performs one HTTP GET against the load balancer endpoint
+// and returns the trimmed body.
+func fetchEndpoint() (string, error) {
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(fmt.Sprintf("http://%s/", lbEndpoint))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("endpoint %s returned %d", lbEndpoint, resp.StatusCode)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+var _ = Describe("Cloud load balancer", func() {
+	It("should be running on a provider with load balancer support", func() {
+		provider = providers.Detect(testcontext.Context(),
+			testcontext.CloudClient(), testcontext.KubeClient(), testcontext.ClusterID())
+		log.Infof("detected provider %q for cluster %s", provider, testcontext.ClusterID())
+
+		if !supportsLoadBalancers[provider] {
+			Skip(fmt.Sprintf("provider %q does not support cloud load balancers", provider))
+		}
+	})
+
+	It("should provision an external endpoint for a LoadBalancer service", func() {
+		if !supportsLoadBalancers[provider] {
+			Skip("no load balancer support")
+		}
+		kube := testcontext.KubeClient()
+
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: lbNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		replicas := int32(2)
+		labels := map[string]string{"app": lbBackend}
+		_, err = kube.AppsV1().Deployments(lbNamespace).Create(testcontext.Context(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: lbBackend},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "backend",
+								Image: lbImage,
+								Command: []string{"sh", "-c", fmt.Sprintf(
+									"mkdir -p /www && echo %s > /www/index.html && httpd -f -p %d -h /www",
+									lbResponse, lbPort)},
+							},
+						},
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("creating a LoadBalancer service and waiting up to %s for an external endpoint", *lbTimeout)
+		_, err = kube.CoreV1().Services(lbNamespace).Create(testcontext.Context(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: lbService},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: labels,
+				Ports: []corev1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromInt(lbPort)},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() (string, error) {
+			service, err := kube.CoreV1().Services(lbNamespace).Get(
+				testcontext.Context(), lbService, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				if ingress.IP != "" {
+					return ingress.IP, nil
+				}
+				if ingress.Hostname != "" {
+					return ingress.Hostname, nil
+				}
+			}
+			return "", nil
+		}, *lbTimeout, lbPoll).ShouldNot(BeEmpty(),
+			"load balancer on provider %q never got an external endpoint", provider)
+
+		service, err := kube.CoreV1().Services(lbNamespace).Get(testcontext.Context(), lbService, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		ingress := service.Status.LoadBalancer.Ingress[0]
+		lbEndpoint = ingress.IP
+		if lbEndpoint == "" {
+			lbEndpoint = ingress.Hostname
+		}
+		log.Infof("load balancer endpoint: %s", lbEndpoint)
+	})
+
+	It("should serve traffic from outside the cluster", func() {
+		if lbEndpoint == "" {
+			Skip("no load balancer endpoint from the provisioning spec")
+		}
+
+		// The first requests after provisioning can fail while DNS and the
+		// provider's health checks settle, so poll rather than fetch once.
+		log.Step("fetching http://%s/ from the test runner", lbEndpoint)
+		Eventually(fetchEndpoint, *lbTimeout, lbPoll).Should(Equal(lbResponse),
+			"load balancer endpoint %s never served the backend response", lbEndpoint)
+	})
+
+	It("should remove the load balancer on service deletion", func() {
+		if lbEndpoint == "" {
+			Skip("no load balancer endpoint from the provisioning spec")
+		}
+		kube := testcontext.KubeClient()
+
+		log.Step("deleting the service and waiting up to %s for the load balancer to go away", *lbTimeout)
+		err := kube.CoreV1().Services(lbNamespace).Delete(testcontext.Context(), lbService, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The service object lingers under a finalizer until the cloud
+		// controller manager has deprovisioned the balancer, so its
+		// disappearance is the cloud-side signal.
+		Eventually(func() bool {
+			_, err := kube.CoreV1().Services(lbNamespace).Get(testcontext.Context(), lbService, metav1.GetOptions{})
+			return apierrors.IsNotFound(err)
+		}, *lbTimeout, lbPoll).Should(BeTrue(),
+			"service %s was never fully deleted; the load balancer may be orphaned", lbService)
+
+		// And from the outside: the endpoint must stop serving.
+		Eventually(func() error {
+			_, err := fetchEndpoint()
+			return err
+		}, *lbTimeout, lbPoll).ShouldNot(Succeed(),
+			"endpoint %s still serves traffic after service deletion", lbEndpoint)
+	})
+
+	It("should clean up the load balancer namespace", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), lbNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,276 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	networkNamespace = "e2e-network"
+	probeDaemonSet   = "net-probe"
+	probeService     = "net-probe"
+	nodePortService  = "net-probe-nodeport"
+	probePort        = 8080
+	probeResponse    = "net-probe-ok"
+
+	// egressEndpoint is the known-external endpoint the egress spec fetches.
+	egressEndpoint = "http://example.com"
+
+	probeReadyTimeout = 5 * time.Minute
+	probePoll         = 10 * time.Second
+	connectTimeout    = 2 * time.Minute
+)
+
+// probeWorkload returns a DaemonSet that lands one HTTP server pod on every
+// node, masters included, so connectivity can be exercised between every
+// pair of nodes and across node pools.
+func probeWorkload() *appsv1.DaemonSet {
+	labels := map[string]string{"app": probeDaemonSet}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   probeDaemonSet,
+			Labels: labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "probe",
+							Image: "docker.io/library/busybox:1.31.1",
+							Command: []string{"sh", "-c", fmt.Sprintf(
+								"mkdir -p /www && echo %s > /www/index.html && httpd -f -p %d -h /www",
+								probeResponse, probePort)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// probePods lists the probe pods, one per node, failing the spec if the
+// DaemonSet isn't fully deployed yet.
+func probePods() []corev1.Pod {
+	pods, err := testcontext.KubeClient().CoreV1().Pods(networkNamespace).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "app=" + probeDaemonSet,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(pods.Items).NotTo(BeEmpty(), "no network probe pods found; did the DaemonSet spec run?")
+	return pods.Items
+}
+
+// wgetFromPod fetches the URL from inside the given probe pod and returns
+// the body. busybox wget exits non-zero on any connection or HTTP failure,
+// which surfaces as an exec error.
+func wgetFromPod(pod *corev1.Pod, url string) (string, error) {
+	kube := testcontext.KubeClient()
+	config, err := k8s.Config(testcontext.ClusterID())
+	if err != nil {
+		return "", err
+	}
+
+	req := kube.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(networkNamespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: "probe",
+			Command:   []string{"wget", "-q", "-O", "-", "-T", "30", url},
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(testcontext.Context(), connectTimeout)
+	defer cancel()
+	var stdout, stderr strings.Builder
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return "", fmt.Errorf("wget %s from pod %s on node %s: %v (stderr: %s)",
+			url, pod.Name, pod.Spec.NodeName, err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// podsOnDistinctNodes returns two probe pods on different nodes, or nil if
+// the cluster only has one node.
+func podsOnDistinctNodes(pods []corev1.Pod) (*corev1.Pod, *corev1.Pod) {
+	for i := range pods[1:] {
+		if pods[i+1].Spec.NodeName != pods[0].Spec.NodeName {
+			return &pods[0], &pods[i+1]
+		}
+	}
+	return nil, nil
+}
+
+var _ = Describe("Cluster network connectivity", func() {
+	It("should run a probe server on every node", func() {
+		kube := testcontext.KubeClient()
+
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: networkNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = kube.AppsV1().DaemonSets(networkNamespace).Create(
+			testcontext.Context(), probeWorkload(), metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Eventually(func() (string, error) {
+			daemonSet, err := kube.AppsV1().DaemonSets(networkNamespace).Get(
+				testcontext.Context(), probeDaemonSet, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			if daemonSet.Status.DesiredNumberScheduled == 0 {
+				return "no pods scheduled yet", nil
+			}
+			if daemonSet.Status.NumberReady != daemonSet.Status.DesiredNumberScheduled {
+				return fmt.Sprintf("%d/%d pods ready",
+					daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled), nil
+			}
+			return "ready", nil
+		}, probeReadyTimeout, probePoll).Should(Equal("ready"),
+			"network probe DaemonSet never became ready on every node")
+	})
+
+	It("should route pod-to-pod traffic across nodes", func() {
+		pods := probePods()
+		source, target := podsOnDistinctNodes(pods)
+		if source == nil {
+			Skip("cluster has a single node; no cross-node path to exercise")
+		}
+		Expect(target.Status.PodIP).NotTo(BeEmpty(), "pod %s has no IP", target.Name)
+
+		log.Step("fetching from pod %s (%s) to pod %s (%s)",
+			source.Name, source.Spec.NodeName, target.Name, target.Spec.NodeName)
+		body, err := wgetFromPod(source, fmt.Sprintf("http://%s:%d", target.Status.PodIP, probePort))
+		Expect(err).NotTo(HaveOccurred(),
+			"pod-to-pod traffic from node %s to node %s failed; check the CNI",
+			source.Spec.NodeName, target.Spec.NodeName)
+		Expect(strings.TrimSpace(body)).To(Equal(probeResponse))
+	})
+
+	It("should route pod-to-service traffic", func() {
+		kube := testcontext.KubeClient()
+
+		service, err := kube.CoreV1().Services(networkNamespace).Create(testcontext.Context(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: probeService},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": probeDaemonSet},
+				Ports: []corev1.ServicePort{
+					{Port: probePort, TargetPort: intstr.FromInt(probePort)},
+				},
+			},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			service, err = kube.CoreV1().Services(networkNamespace).Get(
+				testcontext.Context(), probeService, metav1.GetOptions{})
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(service.Spec.ClusterIP).NotTo(BeEmpty())
+
+		// The cluster IP isolates the service data path (kube-proxy) from
+		// DNS; a DNS-level failure should not fail this spec.
+		pods := probePods()
+		log.Step("fetching the service cluster IP %s from pod %s", service.Spec.ClusterIP, pods[0].Name)
+		Eventually(func() (string, error) {
+			body, err := wgetFromPod(&pods[0], fmt.Sprintf("http://%s:%d", service.Spec.ClusterIP, probePort))
+			return strings.TrimSpace(body), err
+		}, connectTimeout, probePoll).Should(Equal(probeResponse),
+			"pod-to-service traffic via %s failed; check kube-proxy", service.Spec.ClusterIP)
+	})
+
+	It("should reach NodePort services from within the cluster", func() {
+		kube := testcontext.KubeClient()
+
+		service, err := kube.CoreV1().Services(networkNamespace).Create(testcontext.Context(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: nodePortService},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeNodePort,
+				Selector: map[string]string{"app": probeDaemonSet},
+				Ports: []corev1.ServicePort{
+					{Port: probePort, TargetPort: intstr.FromInt(probePort)},
+				},
+			},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			service, err = kube.CoreV1().Services(networkNamespace).Get(
+				testcontext.Context(), nodePortService, metav1.GetOptions{})
+		}
+		Expect(err).NotTo(HaveOccurred())
+		nodePort := service.Spec.Ports[0].NodePort
+		Expect(nodePort).NotTo(BeZero())
+
+		// Fetch another node's NodePort so the path crosses nodes; on a
+		// single-node cluster the node's own port still proves the rule.
+		pods := probePods()
+		source, target := podsOnDistinctNodes(pods)
+		if source == nil {
+			source, target = &pods[0], &pods[0]
+		}
+		Expect(target.Status.HostIP).NotTo(BeEmpty(), "pod %s has no host IP", target.Name)
+
+		log.Step("fetching NodePort %d on node %s from pod %s", nodePort, target.Spec.NodeName, source.Name)
+		Eventually(func() (string, error) {
+			body, err := wgetFromPod(source, fmt.Sprintf("http://%s:%d", target.Status.HostIP, nodePort))
+			return strings.TrimSpace(body), err
+		}, connectTimeout, probePoll).Should(Equal(probeResponse),
+			"NodePort %d on node %s was unreachable from within the cluster", nodePort, target.Spec.NodeName)
+	})
+
+	It("should have external egress", func() {
+		pods := probePods()
+
+		log.Step("fetching %s from pod %s", egressEndpoint, pods[0].Name)
+		body, err := wgetFromPod(&pods[0], egressEndpoint)
+		Expect(err).NotTo(HaveOccurred(),
+			"external egress to %s failed from node %s", egressEndpoint, pods[0].Spec.NodeName)
+		Expect(body).NotTo(BeEmpty())
+	})
+
+	It("should clean up the network probe workload", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), networkNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,113 @@
+package nodechaos
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// diskFillDuration is how long the fill file stays on disk before the
+	// injector removes it. It must exceed the kubelet's eviction monitoring
+	// interval plus enough headroom to observe eviction.
+	diskFillDuration = 10 * time.Minute
+
+	// pressureTimeout bounds how long the kubelet may take to report
+	// DiskPressure once the disk is nearly full; much longer than its
+	// housekeeping interval means misconfigured eviction thresholds.
+	pressureTimeout = 5 * time.Minute
+
+	// pressureClearTimeout allows for the default 5m eviction pressure
+	// transition period after the fill file is removed.
+	pressureClearTimeout = 8 * time.Minute
+
+	diskCanary = "disk-canary"
+
+	// fillCommand fills the root filesystem to ~96% (below 100% so the
+	// node itself keeps functioning, above the default nodefs.available
+	// eviction threshold of 10%), holds, then removes the fill file.
+	fillCommand = "avail=$(df -k / | awk 'NR==2{print $4}'); " +
+		"total=$(df -k / | awk 'NR==2{print $2}'); " +
+		"fill=$((avail - total / 25)); " +
+		"[ $fill -gt 0 ] && dd if=/dev/zero of=/var/e2e-diskfill bs=1024 count=$fill; " +
+		"sleep %d; rm -f /var/e2e-diskfill"
+)
+
+// diskCanaryUID is the pod the disk pressure specs watch for eviction.
+var diskCanaryUID types.UID
+
+// diskCanaryEvicted reports whether the canary pod was evicted for disk
+// pressure: failed in place, marked for deletion, or gone entirely.
+func diskCanaryEvicted() (bool, error) {
+	pods, err := testcontext.KubeClient().CoreV1().Pods(chaosNamespace).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "app=" + diskCanary,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.UID == diskCanaryUID {
+			return pod.Status.Phase == corev1.PodFailed || pod.DeletionTimestamp != nil, nil
+		}
+	}
+	return true, nil
+}
+
+var _ = Describe("Disk pressure on a worker node", func() {
+	It("should report DiskPressure when the disk fills", func() {
+		log.Step("deploying a canary workload onto the target node")
+		diskCanaryUID = deployCanary(diskCanary)
+
+		log.Step("filling the node's ephemeral disk")
+		// The fill heals itself: under sustained DiskPressure the node is
+		// tainted against new pods, so a separate cleanup step might never
+		// schedule.
+		startOnNode("disk-fill", fmt.Sprintf(fillCommand, int(diskFillDuration.Seconds())))
+
+		Eventually(func() (bool, error) {
+			return nodeCondition(corev1.NodeDiskPressure)
+		}, pressureTimeout, statusPoll).Should(BeTrue(),
+			"node %s never reported DiskPressure after its disk filled", targetNode)
+	})
+
+	It("should evict workloads under disk pressure", func() {
+		Eventually(diskCanaryEvicted, evictionTimeout, statusPoll).Should(BeTrue(),
+			"canary pod on %s was not evicted under disk pressure", targetNode)
+	})
+
+	It("should recover once the disk is cleaned up", func() {
+		log.Step("waiting for DiskPressure to clear")
+		Eventually(func() (bool, error) {
+			return nodeCondition(corev1.NodeDiskPressure)
+		}, diskFillDuration+pressureClearTimeout, statusPoll).Should(BeFalse(),
+			"node %s still reports DiskPressure after the fill file was removed", targetNode)
+
+		log.Step("verifying the node is Ready")
+		Eventually(nodeReady, recoveryTimeout, statusPoll).Should(BeTrue(),
+			"node %s is not Ready after disk pressure cleared", targetNode)
+
+		log.Step("verifying the canary workload is running again")
+		Eventually(func() (int32, error) {
+			deployment, err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Get(
+				testcontext.Context(), diskCanary, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, recoveryTimeout, statusPoll).Should(BeNumerically(">", 0),
+			"canary deployment did not recover after disk pressure cleared")
+
+		err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Delete(
+			testcontext.Context(), diskCanary, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,102 @@
+package nodechaos
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// kubeletOutageDuration is how long the kubelet stays stopped before
+	// the injector restarts it. It must exceed the default 300s not-ready
+	// toleration so eviction is observable.
+	kubeletOutageDuration = 7 * time.Minute
+
+	// detectionDeadline bounds how long the node lifecycle controller may
+	// take to flip the node NotReady after the kubelet stops reporting.
+	// The default node-monitor-grace-period is 40s; anything much beyond
+	// that means our distribution has misconfigured it.
+	detectionDeadline = 2 * time.Minute
+
+	rejoinTimeout = 6 * time.Minute
+
+	kubeletCanary = "kubelet-canary"
+)
+
+// kubeletCanaryUID is the pod the kubelet specs watch for eviction.
+var kubeletCanaryUID types.UID
+
+// kubeletStoppedAt is when the outage was injected, for measuring NotReady
+// detection time.
+var kubeletStoppedAt time.Time
+
+var _ = Describe("Kubelet outage on a worker node", func() {
+	It("should detect the stopped kubelet within the monitor grace period", func() {
+		log.Step("deploying a canary workload onto the target node")
+		kubeletCanaryUID = deployCanary(kubeletCanary)
+
+		log.Step("stopping the kubelet")
+		// The outage heals itself: with the kubelet down, no new pod could
+		// be delivered to restart it. The injector survives the kubelet
+		// being stopped because the container runtime keeps running.
+		kubeletStoppedAt = time.Now()
+		startOnNode("kubelet-stop", fmt.Sprintf(
+			"systemctl stop kubelet; sleep %d; systemctl start kubelet",
+			int(kubeletOutageDuration.Seconds())))
+
+		Eventually(nodeReady, detectionDeadline, statusPoll).Should(BeFalse(),
+			"node %s never went NotReady after its kubelet stopped", targetNode)
+		log.Infof("kubelet outage detected after %s",
+			time.Since(kubeletStoppedAt).Round(time.Second))
+	})
+
+	It("should evict workloads per the default not-ready tolerations", func() {
+		Eventually(func() (bool, error) {
+			return canaryPodGone(kubeletCanary, kubeletCanaryUID)
+		}, evictionTimeout, statusPoll).Should(BeTrue(),
+			"canary pod on %s was not evicted while its kubelet was stopped", targetNode)
+	})
+
+	It("should rejoin cleanly once the kubelet restarts", func() {
+		log.Step("waiting for the node to return to Ready")
+		Eventually(nodeReady, kubeletOutageDuration+rejoinTimeout, statusPoll).Should(BeTrue(),
+			"node %s did not return to Ready after its kubelet restarted", targetNode)
+
+		log.Step("verifying the injector pod completed under the restarted kubelet")
+		// The restarted kubelet reconciles the injector's exit with the API,
+		// so a Succeeded phase doubles as proof the rejoin was clean.
+		Eventually(func() (corev1.PodPhase, error) {
+			pod, err := testcontext.KubeClient().CoreV1().Pods(chaosNamespace).Get(
+				testcontext.Context(), "kubelet-stop", metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			return pod.Status.Phase, nil
+		}, rejoinTimeout, statusPoll).Should(Equal(corev1.PodSucceeded),
+			"injector pod never completed after the kubelet restarted")
+
+		log.Step("verifying the canary workload is running again")
+		Eventually(func() (int32, error) {
+			deployment, err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Get(
+				testcontext.Context(), kubeletCanary, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, rejoinTimeout, statusPoll).Should(BeNumerically(">", 0),
+			"canary deployment did not recover after the kubelet restarted")
+
+		err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Delete(
+			testcontext.Context(), kubeletCanary, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,161 @@
+package nodechaos
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var (
+	// workerPoolID is the pool the suite picks its victim node from.
+	workerPoolID string
+	// targetNode is the Kubernetes name of the node chaos is injected into.
+	targetNode string
+)
+
+// The node chaos suite injects node-level failures (network partition,
+// kubelet outage, disk pressure) into one worker node of the cluster under
+// test and verifies the platform detects, reacts to, and recovers from
+// each. The node is expected to be healthy again after every spec.
+func TestNodeChaos(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("node chaos suite disrupts the cluster; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("nodechaos")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("nodechaos")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("nodechaos")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Node Chaos Suite", reporting.Reporters("nodechaos"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the node chaos suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+
+	nodePools, err := client.ListNodePools(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	for i := range nodePools {
+		if !nodePools[i].IsMaster() {
+			workerPoolID = nodePools[i].ID
+			break
+		}
+	}
+	Expect(workerPoolID).NotTo(BeEmpty(), "cluster %s has no worker pools", clusterID)
+
+	kubeClient, err := k8s.New(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetKubeClient(kubeClient)
+
+	// Pick the first node of the worker pool as the victim. Everything the
+	// suite does to it is either self-healing or undone by a spec.
+	nodes, err := kubeClient.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nodes.Items).NotTo(BeEmpty(), "worker pool %s has no Kubernetes nodes", workerPoolID)
+	targetNode = nodes.Items[0].Name
+
+	log.Infof("injecting node chaos into %s (pool %s)", targetNode, workerPoolID)
+
+	return []byte(fmt.Sprintf("%s,%s", workerPoolID, targetNode))
+}, func(data []byte) {
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(2))
+
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+		testcontext.SetClusterID(testcontext.ResolveClusterID())
+
+		kubeClient, err := k8s.New(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetKubeClient(kubeClient)
+	}
+	workerPoolID = parts[0]
+	targetNode = parts[1]
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	// Best-effort: clear the chaos namespace so repeated runs start clean.
+	//nolint:errcheck - cleanup must not fail the suite
+	testcontext.KubeClient().CoreV1().Namespaces().Delete(
+		testcontext.Context(), chaosNamespace, metav1.DeleteOptions{})
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
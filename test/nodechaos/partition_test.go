@@ -0,0 +1,268 @@
+package nodechaos
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// chaosNamespace holds the suite's injector pods and canary workloads.
+	chaosNamespace = "e2e-node-chaos"
+
+	// apiserverPort is the port the partition blocks to isolate the node
+	// from the control plane.
+	apiserverPort = "6443"
+
+	// partitionDuration is how long the injected partition holds before it
+	// heals itself. It must comfortably exceed NotReady detection plus the
+	// default 300s not-ready toleration so eviction is observable.
+	partitionDuration = 7 * time.Minute
+
+	notReadyTimeout = 3 * time.Minute
+	evictionTimeout = 8 * time.Minute
+	recoveryTimeout = 6 * time.Minute
+	statusPoll      = 10 * time.Second
+
+	canaryDeployment = "partition-canary"
+)
+
+// ensureChaosNamespace creates the suite namespace if it doesn't exist yet.
+func ensureChaosNamespace() {
+	_, err := testcontext.KubeClient().CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: chaosNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+// injectorPod builds a privileged host-namespace pod pinned to the target
+// node that runs the given shell command on the host via nsenter. The
+// command executes with the host's own binaries (iptables, systemctl, ...).
+func injectorPod(name, command string) *corev1.Pod {
+	privileged := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			NodeName:      targetNode,
+			RestartPolicy: corev1.RestartPolicyNever,
+			HostPID:       true,
+			HostNetwork:   true,
+			// Tolerate everything so injectors still land on a node the
+			// suite has already degraded.
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{
+				{
+					Name:  "injector",
+					Image: "docker.io/library/busybox:1.31.1",
+					Command: []string{
+						"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "--",
+						"/bin/sh", "-c", command,
+					},
+					SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+				},
+			},
+		},
+	}
+}
+
+// startOnNode creates an injector pod without waiting for it to finish.
+// Used for injections that cut the node off from the control plane, where
+// the kubelet can no longer report pod status anyway.
+func startOnNode(name, command string) {
+	ensureChaosNamespace()
+	_, err := testcontext.KubeClient().CoreV1().Pods(chaosNamespace).Create(
+		testcontext.Context(), injectorPod(name, command), metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// runOnNode creates an injector pod and waits for it to complete
+// successfully.
+func runOnNode(name, command string, timeout time.Duration) {
+	startOnNode(name, command)
+
+	Eventually(func() (corev1.PodPhase, error) {
+		pod, err := testcontext.KubeClient().CoreV1().Pods(chaosNamespace).Get(
+			testcontext.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pod.Status.Phase, nil
+	}, timeout, statusPoll).Should(Equal(corev1.PodSucceeded),
+		"injector pod %s did not complete on node %s", name, targetNode)
+}
+
+// nodeReady reports whether the target node's Ready condition is True.
+func nodeReady() (bool, error) {
+	node, err := testcontext.KubeClient().CoreV1().Nodes().Get(testcontext.Context(), targetNode, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// nodeCondition reports whether the given condition on the target node is
+// True.
+func nodeCondition(conditionType corev1.NodeConditionType) (bool, error) {
+	node, err := testcontext.KubeClient().CoreV1().Nodes().Get(testcontext.Context(), targetNode, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// canaryWorkload returns a pause deployment pinned to the target node so
+// eviction behavior can be observed on it.
+func canaryWorkload(name string) *appsv1.Deployment {
+	labels := map[string]string{"app": name}
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{
+						"kubernetes.io/hostname": targetNode,
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "pause",
+							Image: "k8s.gcr.io/pause:3.2",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deployCanary creates the canary deployment, waits for it to be ready, and
+// returns the UID of its running pod.
+func deployCanary(name string) types.UID {
+	ensureChaosNamespace()
+	kube := testcontext.KubeClient()
+
+	_, err := kube.AppsV1().Deployments(chaosNamespace).Create(
+		testcontext.Context(), canaryWorkload(name), metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	var uid types.UID
+	Eventually(func() (bool, error) {
+		pods, err := kube.CoreV1().Pods(chaosNamespace).List(testcontext.Context(), metav1.ListOptions{
+			LabelSelector: "app=" + name,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+				uid = pod.UID
+				return true, nil
+			}
+		}
+		return false, nil
+	}, 3*time.Minute, statusPoll).Should(BeTrue(),
+		"canary %s never came up on node %s", name, targetNode)
+
+	return uid
+}
+
+// canaryPodGone reports whether the canary pod with the given UID has been
+// evicted: deleted outright or marked for deletion by the node lifecycle
+// controller.
+func canaryPodGone(name string, uid types.UID) (bool, error) {
+	pods, err := testcontext.KubeClient().CoreV1().Pods(chaosNamespace).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "app=" + name,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.UID == uid {
+			return pod.DeletionTimestamp != nil, nil
+		}
+	}
+	return true, nil
+}
+
+// canaryPodUID is the pod the partition specs watch for eviction, recorded
+// before the partition is injected.
+var canaryPodUID types.UID
+
+var _ = Describe("Worker node network partition", func() {
+	It("should mark the partitioned node NotReady", func() {
+		log.Step("deploying a canary workload onto the target node")
+		canaryPodUID = deployCanary(canaryDeployment)
+
+		log.Step("isolating the node from the control plane")
+		// The partition must heal itself: while it holds, the kubelet can't
+		// receive new pods, so a separate heal step could never be delivered.
+		// The injector applies the iptables rule, sleeps, and removes it.
+		rule := fmt.Sprintf("-p tcp --dport %s -j DROP", apiserverPort)
+		startOnNode("partition", fmt.Sprintf(
+			"iptables -I OUTPUT %s; sleep %d; iptables -D OUTPUT %s",
+			rule, int(partitionDuration.Seconds()), rule))
+
+		Eventually(nodeReady, notReadyTimeout, statusPoll).Should(BeFalse(),
+			"node %s never went NotReady after being partitioned", targetNode)
+	})
+
+	It("should evict workloads per the default not-ready tolerations", func() {
+		Eventually(func() (bool, error) {
+			return canaryPodGone(canaryDeployment, canaryPodUID)
+		}, evictionTimeout, statusPoll).Should(BeTrue(),
+			"canary pod on %s was not evicted while the node was NotReady", targetNode)
+	})
+
+	It("should recover once the partition heals", func() {
+		log.Step("waiting for the node to return to Ready")
+		Eventually(nodeReady, partitionDuration+recoveryTimeout, statusPoll).Should(BeTrue(),
+			"node %s did not return to Ready after the partition healed", targetNode)
+
+		log.Step("verifying the canary workload is running again")
+		Eventually(func() (int32, error) {
+			deployment, err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Get(
+				testcontext.Context(), canaryDeployment, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, recoveryTimeout, statusPoll).Should(BeNumerically(">", 0),
+			"canary deployment did not recover after the partition healed")
+
+		err := testcontext.KubeClient().AppsV1().Deployments(chaosNamespace).Delete(
+			testcontext.Context(), canaryDeployment, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,131 @@
+package nodepool
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	defaultPoolCount = 1
+
+	registerTimeout = 10 * time.Minute
+	removalTimeout  = 10 * time.Minute
+	nodeStatusPoll  = 10 * time.Second
+)
+
+// testPoolID is the ID of the pool created by the lifecycle specs, shared
+// so the suite teardown can clean up after a mid-spec failure.
+var testPoolID string
+
+// poolCount returns the node count for the test pool, overridable via
+// environment for soak jobs that want a bigger pool.
+func poolCount() int32 {
+	raw := os.Getenv(constants.NodePoolCountEnvVar)
+	if raw == "" {
+		return defaultPoolCount
+	}
+
+	count, err := strconv.ParseInt(raw, 10, 32)
+	Expect(err).NotTo(HaveOccurred(), "%s must be an integer, got %q", constants.NodePoolCountEnvVar, raw)
+	Expect(count).To(BeNumerically(">", 0), "%s must be positive", constants.NodePoolCountEnvVar)
+
+	return int32(count)
+}
+
+// poolNodeNames returns the Kubernetes node names belonging to the given
+// pool, matched via the node pool ID label the sync controller applies.
+func poolNodeNames(poolID string) ([]string, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, poolID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+
+	return names, nil
+}
+
+var _ = Describe("Node pool lifecycle", func() {
+	var (
+		count       int32
+		poolName    string
+		createStart time.Time
+	)
+
+	It("should create a new worker pool and reach running", func() {
+		count = poolCount()
+		poolName = fmt.Sprintf("e2e-pool-%d", time.Now().Unix())
+
+		// Version and size default to the cluster's version and the
+		// provider default so the suite runs unconfigured on any provider.
+		req := &cloud.CreateNodePoolRequest{
+			Name:              poolName,
+			KubernetesMode:    "worker",
+			KubernetesVersion: os.Getenv(constants.NodePoolKubernetesVersionEnvVar),
+			InstanceSize:      os.Getenv(constants.NodePoolInstanceSizeEnvVar),
+			Count:             count,
+		}
+
+		log.Step("creating node pool %s with %d node(s)", poolName, count)
+		createStart = time.Now()
+		pool, err := testcontext.CloudClient().CreateNodePool(testcontext.ClusterID(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pool.ID).NotTo(BeEmpty())
+		testPoolID = pool.ID
+
+		log.Step("waiting for the pool to reach running")
+		err = testcontext.CloudClient().WaitForNodePoolRunning(testcontext.ClusterID(), testPoolID, constants.ScaleTimeout)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should register the pool's nodes in Kubernetes", func() {
+		Expect(testPoolID).NotTo(BeEmpty(), "pool was not created")
+
+		Eventually(func() (int, error) {
+			names, err := poolNodeNames(testPoolID)
+			return len(names), err
+		}, registerTimeout, nodeStatusPoll).Should(Equal(int(count)),
+			"pool %s should register %d Kubernetes node(s)", poolName, count)
+
+		log.Infof("pool %s registered %d node(s) in %s", poolName, count, time.Since(createStart))
+	})
+
+	It("should delete the pool and remove its nodes", func() {
+		Expect(testPoolID).NotTo(BeEmpty(), "pool was not created")
+
+		log.Step("deleting node pool %s", poolName)
+		err := testcontext.CloudClient().DeleteNodePool(testcontext.ClusterID(), testPoolID)
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the provision API to forget the pool")
+		err = testcontext.CloudClient().WaitForNodePoolDeleted(testcontext.ClusterID(), testPoolID, constants.DeleteTimeout)
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the pool's Kubernetes nodes to disappear")
+		Eventually(func() ([]string, error) {
+			return poolNodeNames(testPoolID)
+		}, removalTimeout, nodeStatusPoll).Should(BeEmpty(),
+			"pool %s's nodes should be removed from Kubernetes", poolName)
+
+		// The pool is gone; nothing left for the suite teardown to sweep.
+		testPoolID = ""
+	})
+})
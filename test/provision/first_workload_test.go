@@ -0,0 +1,193 @@
+package provision
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/severity"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	firstWorkloadNamespace = "e2e-first-workload"
+	firstWorkloadPod       = "first-workload"
+	firstWorkloadPoll      = 10 * time.Second
+
+	// firstWorkloadTimeout bounds how long the spec waits for the probe
+	// after the cluster is already RUNNING; by then the API is reachable,
+	// so anything longer means the probe itself is wedged.
+	firstWorkloadTimeout = 10 * time.Minute
+
+	// firstWorkloadSLO is the target for time-to-first-workload from
+	// cluster creation. Checked at warn severity by default since slower
+	// providers routinely miss it; promote to fail per deployment via the
+	// severity config.
+	firstWorkloadSLO = 15 * time.Minute
+)
+
+// firstWorkloadProbe measures the user-perceived provisioning time: it
+// starts at cluster create time, deploys a pod the moment the Kubernetes
+// API becomes reachable, and records how long until that pod is Running.
+// This often differs substantially from time-to-status=RUNNING, which is
+// what provision_seconds captures.
+type firstWorkloadProbe struct {
+	clusterID string
+	createdAt time.Time
+
+	stop chan struct{}
+	done chan struct{}
+
+	// duration and err are only read after done is closed.
+	duration time.Duration
+	err      error
+}
+
+// workloadProbe is the probe for the current provision attempt.
+var workloadProbe *firstWorkloadProbe
+
+func newFirstWorkloadProbe(clusterID string, createdAt time.Time) *firstWorkloadProbe {
+	return &firstWorkloadProbe{
+		clusterID: clusterID,
+		createdAt: createdAt,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start launches the probe in the background.
+func (p *firstWorkloadProbe) Start() {
+	go p.run()
+}
+
+// Stop aborts the probe. It is safe to call after completion.
+func (p *firstWorkloadProbe) Stop() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+}
+
+// Wait blocks until the probe completes or the timeout elapses, returning
+// the measured duration.
+func (p *firstWorkloadProbe) Wait(timeout time.Duration) (time.Duration, error) {
+	select {
+	case <-p.done:
+		return p.duration, p.err
+	case <-time.After(timeout):
+		return 0, fmt.Errorf("first workload probe did not complete within %s", timeout)
+	}
+}
+
+func (p *firstWorkloadProbe) run() {
+	defer close(p.done)
+
+	kube := p.waitForAPI()
+	if kube == nil {
+		return
+	}
+
+	if p.err = p.deployWorkload(kube); p.err != nil {
+		return
+	}
+
+	if p.err = p.waitForRunning(kube); p.err != nil {
+		return
+	}
+
+	p.duration = time.Since(p.createdAt)
+	metrics.Record("time_to_first_workload_seconds", p.duration)
+
+	// Best-effort cleanup; the cluster is torn down by later stages anyway.
+	//nolint:errcheck
+	kube.CoreV1().Namespaces().Delete(testcontext.Context(), firstWorkloadNamespace, metav1.DeleteOptions{})
+}
+
+// waitForAPI polls until the cluster's Kubernetes API answers a request,
+// returning nil if the probe is stopped first.
+func (p *firstWorkloadProbe) waitForAPI() kubernetes.Interface {
+	for {
+		kube, err := k8s.New(p.clusterID)
+		if err == nil {
+			if _, err := kube.CoreV1().Namespaces().List(testcontext.Context(), metav1.ListOptions{}); err == nil {
+				return kube
+			}
+		}
+
+		select {
+		case <-p.stop:
+			return nil
+		case <-time.After(firstWorkloadPoll):
+		}
+	}
+}
+
+func (p *firstWorkloadProbe) deployWorkload(kube kubernetes.Interface) error {
+	_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: firstWorkloadNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	_, err = kube.CoreV1().Pods(firstWorkloadNamespace).Create(testcontext.Context(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: firstWorkloadPod},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "pause",
+					Image: "k8s.gcr.io/pause:3.2",
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (p *firstWorkloadProbe) waitForRunning(kube kubernetes.Interface) error {
+	for {
+		pod, err := kube.CoreV1().Pods(firstWorkloadNamespace).Get(
+			testcontext.Context(), firstWorkloadPod, metav1.GetOptions{})
+		if err == nil && pod.Status.Phase == corev1.PodRunning {
+			return nil
+		}
+
+		select {
+		case <-p.stop:
+			return fmt.Errorf("probe stopped before the first workload was Running")
+		case <-time.After(firstWorkloadPoll):
+		}
+	}
+}
+
+var _ = Describe("Time to first workload", func() {
+	It("should run a first user workload and record the time to it", func() {
+		if workloadProbe == nil {
+			Skip("no first workload probe was started for this run")
+		}
+
+		duration, err := workloadProbe.Wait(firstWorkloadTimeout)
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Infof("first user workload Running after %s (cluster %s)",
+			duration.Round(time.Second), testcontext.ClusterID())
+		Expect(duration).To(BeNumerically(">", 0))
+
+		severity.Check("first-workload-slo", severity.Warn, duration <= firstWorkloadSLO,
+			"first workload took %s, over the %s SLO", duration.Round(time.Second), firstWorkloadSLO)
+	})
+})
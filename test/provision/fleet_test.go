@@ -0,0 +1,122 @@
+package provision
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/util"
+)
+
+// clusterCount provisions a fleet of N clusters from the one suite
+// template instead of a single cluster. This is the capacity/limit test
+// for the provisioning pipeline itself; it replaces running N copies of
+// the suite by hand.
+var clusterCount = flag.Int("cluster-count", 1,
+	"number of clusters to provision concurrently (the first is the cluster under test)")
+
+// fleetResult is one fleet member's outcome in the fleet artifact.
+type fleetResult struct {
+	Index            int     `json:"index"`
+	ClusterID        string  `json:"cluster_id,omitempty"`
+	ProvisionSeconds float64 `json:"provision_seconds,omitempty"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// provisionFleetMember provisions one additional cluster from the suite
+// template, waits for it to reach RUNNING, exports its kubeconfig, and
+// tears it down again. The suite's primary cluster is fleet member 0 and
+// is left alone for the rest of the pipeline.
+func provisionFleetMember(index int) fleetResult {
+	result := fleetResult{Index: index}
+	client := testcontext.CloudClient()
+
+	req := &cloud.CreateClusterRequest{
+		TemplateID: testcontext.TemplateID(),
+		Name:       fmt.Sprintf("e2e-fleet-%d-%d", index, time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	}
+
+	var cluster *cloud.Cluster
+	err := util.RetryCloudCall(func() error {
+		var err error
+		cluster, err = client.CreateCluster(req)
+		return err
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ClusterID = cluster.ID
+	defer func() {
+		//nolint:errcheck - best-effort teardown; the sweeper catches strays
+		client.DeleteCluster(cluster.ID)
+		//nolint:errcheck
+		client.WaitForClusterDeleted(cluster.ID, config.DeleteTimeout())
+	}()
+
+	provisionStart := time.Now()
+	if err := client.WaitForClusterRunning(cluster.ID, config.ProvisionTimeout()); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ProvisionSeconds = time.Since(provisionStart).Seconds()
+
+	if err := writeFleetKubeconfig(index, cluster.ID); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// writeFleetKubeconfig exports the kubeconfig of a fleet member as an
+// artifact, so capacity runs leave N usable kubeconfigs behind.
+func writeFleetKubeconfig(index int, clusterID string) error {
+	kubeconfig, err := k8s.Kubeconfig(clusterID)
+	if err != nil {
+		return err
+	}
+	return artifacts.WriteFile(fmt.Sprintf("kubeconfig-fleet-%d.yaml", index), kubeconfig)
+}
+
+var _ = Describe("Provisioning a cluster fleet", func() {
+	It("should provision every fleet member to RUNNING concurrently", func() {
+		if *clusterCount <= 1 {
+			Skip("--cluster-count is 1; no fleet requested")
+		}
+
+		// Member 0 is the suite's primary cluster, already RUNNING by the
+		// time specs execute; export its kubeconfig alongside the rest.
+		Expect(writeFleetKubeconfig(0, testcontext.ClusterID())).To(Succeed())
+
+		extras := *clusterCount - 1
+		results := make([]fleetResult, extras)
+		var wg sync.WaitGroup
+		for i := 0; i < extras; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				results[i] = provisionFleetMember(i + 1)
+			}(i)
+		}
+		wg.Wait()
+
+		//nolint:errcheck - artifact export is best-effort
+		artifacts.WriteJSON("cluster-fleet.json", results)
+
+		for _, result := range results {
+			Expect(result.Error).To(BeEmpty(),
+				"fleet member %d (cluster %s) failed: %s", result.Index, result.ClusterID, result.Error)
+		}
+	})
+})
@@ -0,0 +1,203 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/severity"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The kubelet specs exercise the apiserver→kubelet path on every node:
+// logs, exec, and the kubelet's serving endpoint itself. A kubelet serving
+// certificate that the apiserver won't trust is a classic provisioning bug
+// that leaves the cluster looking healthy until someone runs kubectl logs.
+const (
+	kubeletNamespace = "e2e-kubelet"
+	kubeletDaemonSet = "kubelet-probe"
+	kubeletLogMarker = "kubelet-probe-ok"
+
+	kubeletProbeTimeout = 5 * time.Minute
+	kubeletProbePoll    = 10 * time.Second
+	kubeletExecTimeout  = 2 * time.Minute
+)
+
+// kubeletProbeWorkload returns a DaemonSet that lands one probe pod on
+// every node, masters included, so logs and exec can be verified against
+// each kubelet individually.
+func kubeletProbeWorkload() *appsv1.DaemonSet {
+	labels := map[string]string{"app": kubeletDaemonSet}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   kubeletDaemonSet,
+			Labels: labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "probe",
+							Image:   "docker.io/library/busybox:1.31.1",
+							Command: []string{"sh", "-c", fmt.Sprintf("echo %s && sleep 86400", kubeletLogMarker)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// kubeletProbePods lists the probe pods, one per node, failing the spec if
+// the DaemonSet isn't fully deployed yet.
+func kubeletProbePods(kube kubernetes.Interface) []corev1.Pod {
+	pods, err := kube.CoreV1().Pods(kubeletNamespace).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "app=" + kubeletDaemonSet,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(pods.Items).NotTo(BeEmpty(), "no kubelet probe pods found; did the DaemonSet spec run?")
+	return pods.Items
+}
+
+var _ = Describe("Kubelet API path", func() {
+	var kube kubernetes.Interface
+
+	BeforeEach(func() {
+		var err error
+		kube, err = k8s.New(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should run a probe pod on every node", func() {
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: kubeletNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = kube.AppsV1().DaemonSets(kubeletNamespace).Create(
+			testcontext.Context(), kubeletProbeWorkload(), metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Eventually(func() (string, error) {
+			daemonSet, err := kube.AppsV1().DaemonSets(kubeletNamespace).Get(
+				testcontext.Context(), kubeletDaemonSet, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			if daemonSet.Status.DesiredNumberScheduled == 0 {
+				return "no pods scheduled yet", nil
+			}
+			if daemonSet.Status.NumberReady != daemonSet.Status.DesiredNumberScheduled {
+				return fmt.Sprintf("%d/%d pods ready",
+					daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled), nil
+			}
+			return "ready", nil
+		}, kubeletProbeTimeout, kubeletProbePoll).Should(Equal("ready"),
+			"kubelet probe DaemonSet never became ready on every node")
+	})
+
+	It("should stream logs from a pod on every node", func() {
+		for _, pod := range kubeletProbePods(kube) {
+			logs, err := kube.CoreV1().Pods(kubeletNamespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).
+				DoRaw(testcontext.Context())
+			Expect(err).NotTo(HaveOccurred(),
+				"kubectl logs failed for pod %s on node %s", pod.Name, pod.Spec.NodeName)
+			Expect(string(logs)).To(ContainSubstring(kubeletLogMarker),
+				"unexpected log output from pod %s on node %s", pod.Name, pod.Spec.NodeName)
+		}
+	})
+
+	It("should exec into a pod on every node", func() {
+		config, err := k8s.Config(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, pod := range kubeletProbePods(kube) {
+			marker := fmt.Sprintf("kubelet-exec-ok-%s", pod.Spec.NodeName)
+
+			req := kube.CoreV1().RESTClient().Post().
+				Resource("pods").
+				Namespace(kubeletNamespace).
+				Name(pod.Name).
+				SubResource("exec").
+				VersionedParams(&corev1.PodExecOptions{
+					Container: "probe",
+					Command:   []string{"echo", marker},
+					Stdout:    true,
+					Stderr:    true,
+				}, scheme.ParameterCodec)
+
+			executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(testcontext.Context(), kubeletExecTimeout)
+			var stdout, stderr strings.Builder
+			err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+				Stdout: &stdout,
+				Stderr: &stderr,
+			})
+			cancel()
+			Expect(err).NotTo(HaveOccurred(),
+				"kubectl exec failed for pod %s on node %s (stderr: %s)", pod.Name, pod.Spec.NodeName, stderr.String())
+			Expect(stdout.String()).To(ContainSubstring(marker))
+		}
+	})
+
+	It("should serve every node's kubelet endpoint over verified TLS", func() {
+		nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes.Items).NotTo(BeEmpty())
+
+		// The apiserver verifies the kubelet's serving certificate when it
+		// opens this proxy connection, so a healthz through it per node is
+		// the serving-cert validity check from the path that matters. The
+		// check runs through the severity config so providers with known-
+		// loose kubelet TLS can demote it to warn instead of quarantining
+		// the whole spec.
+		for _, node := range nodes.Items {
+			body, err := kube.CoreV1().RESTClient().Get().
+				Resource("nodes").
+				Name(node.Name).
+				SubResource("proxy").
+				Suffix("healthz").
+				DoRaw(testcontext.Context())
+			severity.Check("kubelet-serving-tls", severity.Fail, err == nil,
+				"apiserver could not reach the kubelet on node %s; check its serving certificate: %v", node.Name, err)
+			if err != nil {
+				continue
+			}
+			severity.Check("kubelet-serving-tls", severity.Fail, string(body) == "ok",
+				"unexpected healthz response from the kubelet on node %s: %q", node.Name, body)
+		}
+	})
+
+	It("should clean up the kubelet probe workload", func() {
+		err := kube.CoreV1().Namespaces().Delete(testcontext.Context(), kubeletNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
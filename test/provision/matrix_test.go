@@ -0,0 +1,151 @@
+package provision
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/templates"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// matrixParallel provisions the additional matrix versions concurrently.
+// Sequential is the default: parallel clusters multiply provider quota
+// usage, which shared CI orgs don't always have headroom for.
+var matrixParallel = flag.Bool("matrix-parallel", false,
+	"provision additional --kubernetes-version matrix entries in parallel")
+
+// matrixVersions is populated by the suite setup: every resolved version
+// after the first. The first version is the cluster under test as always;
+// matrix cells re-render the run's selected template per version.
+var matrixVersions []string
+
+// matrixResult is one version cell's outcome in the matrix artifact.
+type matrixResult struct {
+	Version          string  `json:"version"`
+	ClusterID        string  `json:"cluster_id,omitempty"`
+	ProvisionSeconds float64 `json:"provision_seconds,omitempty"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// provisionMatrixCell provisions a cluster at the given version, validates
+// it superficially (running, all nodes registered in Kubernetes), and
+// tears it down again. Matrix cells get the quick validation only; the
+// full spec battery runs against the primary cluster.
+func provisionMatrixCell(version string) matrixResult {
+	result := matrixResult{Version: version}
+	client := testcontext.CloudClient()
+
+	vars := templates.DefaultVars(version)
+	vars["master_count"] = strconv.Itoa(*masterCount)
+	vars["worker_count"] = strconv.Itoa(*workerCount)
+
+	rendered, err := renderSelectedTemplate(vars)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	template, err := client.CreateTemplate(rendered.Request)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer func() {
+		//nolint:errcheck - best-effort teardown; the sweeper catches strays
+		client.DeleteTemplate(template.ID)
+	}()
+
+	cluster, err := client.CreateCluster(matrixClusterRequest(template.ID, version))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ClusterID = cluster.ID
+	defer func() {
+		//nolint:errcheck - best-effort teardown; the sweeper catches strays
+		client.DeleteCluster(cluster.ID)
+		//nolint:errcheck
+		client.WaitForClusterDeleted(cluster.ID, config.DeleteTimeout())
+	}()
+
+	provisionStart := time.Now()
+	if err := client.WaitForClusterRunning(cluster.ID, config.ProvisionTimeout()); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ProvisionSeconds = time.Since(provisionStart).Seconds()
+
+	kube, err := k8s.New(cluster.ID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if want := *masterCount + *workerCount; len(nodes.Items) != want {
+		result.Error = fmt.Sprintf("expected %d Kubernetes nodes, found %d", want, len(nodes.Items))
+	}
+
+	return result
+}
+
+var _ = Describe("Kubernetes version matrix", func() {
+	It("should provision and validate each additional matrix version", func() {
+		if len(matrixVersions) == 0 {
+			Skip("--kubernetes-version resolved to a single version")
+		}
+
+		results := make([]matrixResult, len(matrixVersions))
+		if *matrixParallel {
+			var wg sync.WaitGroup
+			for i, version := range matrixVersions {
+				wg.Add(1)
+				go func(i int, version string) {
+					defer wg.Done()
+					defer GinkgoRecover()
+					results[i] = provisionMatrixCell(version)
+				}(i, version)
+			}
+			wg.Wait()
+		} else {
+			for i, version := range matrixVersions {
+				results[i] = provisionMatrixCell(version)
+			}
+		}
+
+		//nolint:errcheck - artifact export is best-effort
+		artifacts.WriteJSON("version-matrix.json", results)
+
+		for _, result := range results {
+			Expect(result.Error).To(BeEmpty(),
+				"matrix version %s failed: %s", result.Version, result.Error)
+		}
+	})
+})
+
+// matrixClusterRequest builds the create request for a matrix cell,
+// carrying the same ownership labels as the primary cluster so leftovers
+// stay traceable.
+func matrixClusterRequest(templateID, version string) *cloud.CreateClusterRequest {
+	return &cloud.CreateClusterRequest{
+		TemplateID: templateID,
+		Name:       fmt.Sprintf("e2e-matrix-%s-%d", version, time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	}
+}
@@ -0,0 +1,488 @@
+package provision
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/lock"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/providers"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/state"
+	"github.com/containership/e2e-test/pkg/templates"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/timeline"
+	"github.com/containership/e2e-test/pkg/util"
+	"github.com/containership/e2e-test/pkg/versions"
+)
+
+const defaultTemplatePath = "../../templates/digitalocean.yaml"
+
+// statePoller samples cluster and node pool statuses in the background for
+// the whole run; the resulting timeline is written out at suite teardown.
+var statePoller *timeline.Poller
+
+// provisionAttempts is the total number of times to attempt provisioning.
+// If an attempt fails for a retryable reason (provider capacity, transient
+// API errors), the failed cluster is deleted and a fresh one is created;
+// the failed attempt is reported as a flake.
+var provisionAttempts = flag.Int("provision-attempts", 1, "number of times to attempt provisioning a fresh cluster on retryable failures")
+
+// kubernetesVersion selects the Kubernetes version(s) to render templates
+// with. "latest", "latest-N", and "N.M.x" are resolved against the versions
+// the API reports as supported, so nightly jobs don't need manual bumps; a
+// comma-separated list provisions a whole version matrix in one run. When
+// the flag is unset, the shared config file and then the built-in default
+// apply.
+var kubernetesVersion = flag.String("kubernetes-version", "",
+	"Kubernetes version(s) to provision (a concrete version, latest, latest-N, N.M.x, or a comma-separated matrix)")
+
+// cleanupOnFailure enables a safety-net teardown: if provisioning dies
+// between the create request and RUNNING (or any spec fails), whatever
+// cluster and template IDs made it into the context are deleted at suite
+// end. Off by default because a failed cluster is usually worth keeping
+// around for a post-mortem; CI turns it on so nothing leaks.
+var cleanupOnFailure = flag.Bool("cleanup-on-failure", false,
+	"delete the cluster and template at suite end if provisioning or any spec failed")
+
+// suiteFailed records whether anything went wrong on this node, for the
+// safety-net teardown.
+var suiteFailed bool
+
+// Pool counts let one template provision different shapes (1/1, 3/3,
+// 3/10). Verification specs derive expected node totals from these rather
+// than assuming template defaults.
+var (
+	masterCount = flag.Int("master-count", 1, "node count for the master pool")
+	workerCount = flag.Int("worker-count", 2, "node count for the worker pool")
+)
+
+// Template selection: an explicit file always wins (flag, then
+// environment, then config file); with no file, --builtin-template picks
+// one from the library embedded in pkg/templates, so the standard flow
+// needs no external template file at all.
+var (
+	templateFlag = flag.String("template", "",
+		"path to a provision template file (overrides the environment, config file, and --builtin-template)")
+	builtinTemplate = flag.String("builtin-template", "",
+		"built-in template to provision from, as provider/os (e.g. aws/ubuntu1804)")
+)
+
+// selectedTemplatePath is the template file this run provisions from;
+// empty when a built-in template was selected instead.
+var selectedTemplatePath string
+
+// renderSelectedTemplate renders whichever template this run selected:
+// the file when one was given, the named built-in otherwise. The version
+// matrix spec re-renders per version through the same selection.
+func renderSelectedTemplate(vars map[string]string) (*templates.Result, error) {
+	if selectedTemplatePath != "" {
+		return templates.Render(selectedTemplatePath, vars)
+	}
+	return templates.RenderBuiltin(*builtinTemplate, vars)
+}
+
+// Instance size overrides are patched into the parsed template rather than
+// rendered as variables, so they work against any template file without
+// editing it. Empty keeps the template's sizes.
+var (
+	masterInstanceSize = flag.String("master-instance-size", "", "instance size for the master pool (empty keeps the template's)")
+	workerInstanceSize = flag.String("worker-instance-size", "", "instance size for the worker pool (empty keeps the template's)")
+)
+
+// resolveKubernetesVersions resolves the version spec (flag, then config
+// file, then the built-in default) against the supported versions reported
+// by the API when needed. The spec may be a comma-separated matrix; see
+// versions.ResolveMatrix.
+func resolveKubernetesVersions(client *cloud.Client) ([]string, error) {
+	spec := *kubernetesVersion
+	if spec == "" {
+		file, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+		spec = file.KubernetesVersion
+	}
+	if spec == "" {
+		spec = constants.DefaultKubernetesVersion
+	}
+
+	// A single concrete version needs no API round trip; anything with a
+	// wildcard, a latest form, or multiple entries does.
+	if !strings.Contains(spec, ",") && !strings.Contains(spec, "latest") && !strings.HasSuffix(spec, ".x") {
+		return []string{spec}, nil
+	}
+
+	supported, err := client.ListSupportedKubernetesVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	return versions.ResolveMatrix(spec, supported)
+}
+
+// provisionFlake records a failed-but-retried provision attempt.
+type provisionFlake struct {
+	Attempt   int    `json:"attempt"`
+	ClusterID string `json:"cluster_id,omitempty"`
+	Error     string `json:"error"`
+}
+
+// provisionCluster creates a cluster from the template and waits for it to
+// be running, retrying with a fresh cluster up to attempts times on
+// retryable failures. Flaked attempts are written to an artifact.
+func provisionCluster(client *cloud.Client, templateID string, providerOptions json.RawMessage, attempts int) (string, error) {
+	var flakes []provisionFlake
+
+	defer func() {
+		if len(flakes) > 0 {
+			//nolint:errcheck - flake reporting must not mask the real result
+			artifacts.WriteJSON("provision-flakes.json", flakes)
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		clusterID, err := provisionClusterOnce(client, templateID, providerOptions)
+		if err == nil {
+			return clusterID, nil
+		}
+
+		if attempt >= attempts || !cloud.IsRetryableProvisionError(err) {
+			// Hand the last attempt's cluster ID back so the safety-net
+			// teardown can find the half-provisioned cluster.
+			return clusterID, err
+		}
+
+		log.Warnf("provision attempt %d failed (retryable), retrying with a fresh cluster: %v", attempt, err)
+		flakes = append(flakes, provisionFlake{
+			Attempt:   attempt,
+			ClusterID: clusterID,
+			Error:     err.Error(),
+		})
+
+		if clusterID != "" {
+			//nolint:errcheck - best-effort cleanup of the failed cluster
+			client.DeleteCluster(clusterID)
+			//nolint:errcheck
+			client.WaitForClusterDeleted(clusterID, config.DeleteTimeout())
+		}
+	}
+}
+
+// provisionClusterOnce performs a single provision attempt. It returns the
+// cluster ID even on failure so the caller can clean up. The background
+// state poller is started for each attempt; on failure its timeline is
+// flushed so the failed attempt can still be analyzed.
+func provisionClusterOnce(client *cloud.Client, templateID string, providerOptions json.RawMessage) (string, error) {
+	clusterReq := &cloud.CreateClusterRequest{
+		TemplateID:      templateID,
+		Name:            fmt.Sprintf("e2e-%d", time.Now().Unix()),
+		ProviderOptions: providerOptions,
+		// Stamp who/what created this cluster so leftovers in the shared
+		// org are traceable to a run.
+		Labels: ownership.Labels(),
+	}
+	//nolint:errcheck - artifact export is best-effort
+	artifacts.WriteJSON("create-cluster-request.json", clusterReq)
+
+	var cluster *cloud.Cluster
+	err := util.RetryCloudCall(func() error {
+		var err error
+		cluster, err = client.CreateCluster(clusterReq)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	statePoller = timeline.NewPoller(client, cluster.ID, constants.TimelinePollInterval)
+	statePoller.Start()
+
+	// Measure user-perceived provisioning time in parallel: the probe
+	// deploys a workload the moment the Kubernetes API answers, which is
+	// usually well before the cluster reports RUNNING.
+	workloadProbe = newFirstWorkloadProbe(cluster.ID, time.Now())
+	workloadProbe.Start()
+
+	if err := client.WaitForClusterRunning(cluster.ID, config.ProvisionTimeout()); err != nil {
+		statePoller.Stop()
+		//nolint:errcheck - a partial timeline is better than none
+		statePoller.WriteArtifact()
+		statePoller = nil
+		workloadProbe.Stop()
+		workloadProbe = nil
+		return cluster.ID, err
+	}
+
+	return cluster.ID, nil
+}
+
+func TestProvision(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("provision suite mutates org state; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("provision")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("provision")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("provision")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Provision Suite", reporting.Reporters("provision"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	// Serialize runs against the shared test org so concurrent pipelines
+	// (and the sweeper) don't trip over each other's clusters. The lock
+	// is held until the cleanup suite releases it.
+	if orgLock := lock.FromEnv(); orgLock != nil {
+		err := orgLock.Acquire(constants.LockAcquireTimeout)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	configFile, err := config.Load()
+	Expect(err).NotTo(HaveOccurred())
+
+	templatePath := *templateFlag
+	if templatePath == "" {
+		templatePath = os.Getenv(constants.TemplatePathEnvVar)
+	}
+	if templatePath == "" {
+		templatePath = configFile.TemplatePath
+	}
+	if templatePath == "" && configFile.Provider != "" {
+		templatePath = fmt.Sprintf("../../templates/%s.yaml", configFile.Provider)
+	}
+	if templatePath == "" && *builtinTemplate == "" {
+		templatePath = defaultTemplatePath
+	}
+	selectedTemplatePath = templatePath
+
+	resolvedVersions, err := resolveKubernetesVersions(client)
+	Expect(err).NotTo(HaveOccurred())
+	// The first matrix version becomes the cluster under test for the rest
+	// of the pipeline; any others are provisioned and validated by the
+	// version matrix spec and torn down again.
+	resolvedVersion := resolvedVersions[0]
+	matrixVersions = resolvedVersions[1:]
+	log.Infof("provisioning Kubernetes %s (matrix: %s)",
+		resolvedVersion, strings.Join(resolvedVersions, ", "))
+
+	vars := templates.DefaultVars(resolvedVersion)
+	vars["master_count"] = strconv.Itoa(*masterCount)
+	vars["worker_count"] = strconv.Itoa(*workerCount)
+
+	// Templates that declare ssh_public_key get the configured key; ones
+	// that don't just produce an unused-variable lint warning.
+	if configFile.SSHPublicKeyPath != "" {
+		key, err := os.ReadFile(configFile.SSHPublicKeyPath)
+		Expect(err).NotTo(HaveOccurred())
+		vars["ssh_public_key"] = strings.TrimSpace(string(key))
+	}
+
+	rendered, err := renderSelectedTemplate(vars)
+	Expect(err).NotTo(HaveOccurred())
+	for _, warning := range rendered.Warnings {
+		log.Warnf("%s", warning)
+	}
+	templateReq := rendered.Request
+
+	// Patch the parsed configuration as well: the count vars above only
+	// take effect in templates that reference them, while the patch pins
+	// the POSTed topology (and any requested instance sizes) regardless of
+	// the template file. The topology specs assert against these values.
+	err = templates.PatchTopology(templateReq, templates.Topology{
+		MasterCount:        *masterCount,
+		WorkerCount:        *workerCount,
+		MasterInstanceSize: *masterInstanceSize,
+		WorkerInstanceSize: *workerInstanceSize,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	// Export exactly what we're about to ask the provision API for, so
+	// failures are diagnosable without re-running.
+	renderedExt := filepath.Ext(templatePath)
+	if renderedExt == "" {
+		// Built-in templates are YAML.
+		renderedExt = ".yaml"
+	}
+	//nolint:errcheck - artifact export is best-effort
+	artifacts.WriteFile("rendered-template"+renderedExt, rendered.Rendered)
+	//nolint:errcheck
+	artifacts.WriteJSON("create-template-request.json", templateReq)
+
+	var template *cloud.Template
+	err = util.RetryCloudCall(func() error {
+		var err error
+		template, err = client.CreateTemplate(templateReq)
+		return err
+	})
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetTemplateID(template.ID)
+
+	providerOptions, err := providers.LoadOptionsFromEnv()
+	Expect(err).NotTo(HaveOccurred())
+	clusterOptions, err := providerOptions.ForProvider(templateReq.ProviderName)
+	Expect(err).NotTo(HaveOccurred(), "invalid provider options for %s", templateReq.ProviderName)
+
+	provisionStart := time.Now()
+	clusterID, err := provisionCluster(client, template.ID, clusterOptions, *provisionAttempts)
+	// Record the ID (even of a failed cluster) before asserting, so the
+	// safety-net teardown knows what to delete.
+	testcontext.SetClusterID(clusterID)
+	if err != nil {
+		suiteFailed = true
+	}
+	Expect(err).NotTo(HaveOccurred())
+	metrics.Record("provision_seconds", time.Since(provisionStart))
+
+	cluster, err := client.GetCluster(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	metrics.SetRunInfo(templateReq.ProviderName, cluster.KubernetesVersion)
+	flakiness.SetProvider(templateReq.ProviderName)
+
+	// Hand the IDs to later pipeline stages when orchestrated.
+	if statePath := os.Getenv(constants.StateFileEnvVar); statePath != "" {
+		err := state.Save(statePath, &state.State{
+			ClusterID:  clusterID,
+			TemplateID: template.ID,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	return []byte(fmt.Sprintf("%s,%s", clusterID, template.ID))
+}, func(data []byte) {
+	// Non-primary nodes only need the IDs and their own client.
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(2))
+
+	if testcontext.CloudClient() == nil {
+		client, err := cloud.New()
+		Expect(err).NotTo(HaveOccurred())
+		testcontext.SetCloudClient(client)
+	}
+	testcontext.SetClusterID(parts[0])
+	testcontext.SetTemplateID(parts[1])
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	if statePoller != nil {
+		statePoller.Stop()
+		err := statePoller.WriteArtifact()
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Make sure the probe goroutine is gone before the leak check runs.
+	if workloadProbe != nil {
+		workloadProbe.Stop()
+	}
+
+	if err := metrics.Flush(); err != nil {
+		log.Warnf("flushing run metrics: %v", err)
+	}
+
+	if *cleanupOnFailure && suiteFailed {
+		safetyNetTeardown()
+	}
+})
+
+// safetyNetTeardown deletes whatever the failed run managed to create.
+// Cleanup normally belongs to the cleanup suite, but that suite never runs
+// when provisioning dies between the create request and RUNNING — which is
+// exactly when clusters used to leak.
+func safetyNetTeardown() {
+	client := testcontext.CloudClient()
+	if client == nil {
+		return
+	}
+
+	if clusterID := testcontext.ClusterID(); clusterID != "" {
+		log.Infof("safety net: deleting cluster %s after a failed run", clusterID)
+		//nolint:errcheck - best-effort teardown; the sweeper catches strays
+		client.DeleteCluster(clusterID)
+		//nolint:errcheck
+		client.WaitForClusterDeleted(clusterID, config.DeleteTimeout())
+	}
+
+	if templateID := testcontext.TemplateID(); templateID != "" {
+		log.Infof("safety net: deleting template %s after a failed run", templateID)
+		//nolint:errcheck - best-effort teardown; the sweeper catches strays
+		client.DeleteTemplate(templateID)
+	}
+}
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+	if desc.Failed {
+		suiteFailed = true
+	}
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
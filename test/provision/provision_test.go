@@ -0,0 +1,115 @@
+package provision
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/verify"
+)
+
+var _ = Describe("Provisioning a cluster", func() {
+	It("should result in a running cluster", func() {
+		cluster, err := testcontext.CloudClient().GetCluster(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Type).To(Equal(cloud.ClusterStatusRunning))
+	})
+
+	It("should result in running node pools", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodePools).NotTo(BeEmpty())
+
+		for _, nodePool := range nodePools {
+			Expect(nodePool.Status.Type).To(Equal(cloud.NodePoolStatusRunning),
+				"node pool %s (%s) is not running", nodePool.Name, nodePool.ID)
+		}
+	})
+
+	It("should create the expected provider-side resources", func() {
+		template, err := testcontext.CloudClient().GetTemplate(testcontext.TemplateID())
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier, err := verify.ForProvider(template.ProviderName)
+		Expect(err).NotTo(HaveOccurred())
+		if verifier == nil {
+			Skip(fmt.Sprintf("no provider verifier configured for %s", template.ProviderName))
+		}
+
+		cluster, err := testcontext.CloudClient().GetCluster(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(verifier.VerifyProvisioned(cluster, nodePools)).To(Succeed())
+	})
+
+	It("should have the requested node counts per pool", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		var masters, workers int32
+		for _, nodePool := range nodePools {
+			if nodePool.IsMaster() {
+				masters += nodePool.Count
+			} else {
+				workers += nodePool.Count
+			}
+		}
+
+		// Expected totals come from the request, not template defaults.
+		Expect(masters).To(Equal(int32(*masterCount)), "master node total")
+		Expect(workers).To(Equal(int32(*workerCount)), "worker node total")
+	})
+
+	It("should have the requested instance sizes per pool", func() {
+		if *masterInstanceSize == "" && *workerInstanceSize == "" {
+			Skip("no instance size override was requested; template defaults apply")
+		}
+
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, nodePool := range nodePools {
+			expected := *workerInstanceSize
+			if nodePool.IsMaster() {
+				expected = *masterInstanceSize
+			}
+			if expected == "" {
+				continue
+			}
+			Expect(nodePool.InstanceSize).To(Equal(expected),
+				"node pool %s (%s) instance size", nodePool.Name, nodePool.ID)
+		}
+	})
+
+	It("should emit an audit event for the cluster creation", func() {
+		events, err := testcontext.CloudClient().ListEvents()
+		if cloud.IsNotFound(err) {
+			Skip("events API not available in this environment")
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		event := cloud.FindEvent(events, cloud.EventClusterCreated, testcontext.ClusterID())
+		Expect(event).NotTo(BeNil(), "no %s event for cluster %s", cloud.EventClusterCreated, testcontext.ClusterID())
+		Expect(event.ActorID).NotTo(BeEmpty(), "audit event is not attributable to an actor")
+	})
+
+	It("should have at least one master node pool", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		foundMaster := false
+		for _, nodePool := range nodePools {
+			np := nodePool
+			if np.IsMaster() {
+				foundMaster = true
+				break
+			}
+		}
+		Expect(foundMaster).To(BeTrue(), "cluster has no master node pool")
+	})
+})
@@ -0,0 +1,198 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	proxyNamespace = "e2e-proxy"
+
+	// concurrentWatches is sized above the per-client connection limits
+	// we've been bitten by before, without being abusive to the proxy.
+	concurrentWatches = 50
+	concurrentExecs   = 10
+
+	watchEventTimeout = 2 * time.Minute
+	execTimeout       = 2 * time.Minute
+	podReadyTimeout   = 3 * time.Minute
+	statusPoll        = 5 * time.Second
+
+	execPod = "exec-target"
+)
+
+// ensureProxyNamespace creates the suite namespace if it doesn't exist yet.
+func ensureProxyNamespace() {
+	_, err := testcontext.KubeClient().CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: proxyNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
+
+var _ = Describe("Proxy connection concurrency", func() {
+	It("should deliver events on many concurrent watch connections", func() {
+		kube := testcontext.KubeClient()
+		ensureProxyNamespace()
+
+		log.Step("opening %d watch connections through the proxy", concurrentWatches)
+		watchers := make([]watch.Interface, 0, concurrentWatches)
+		defer func() {
+			for _, w := range watchers {
+				w.Stop()
+			}
+		}()
+		for i := 0; i < concurrentWatches; i++ {
+			w, err := kube.CoreV1().ConfigMaps(proxyNamespace).Watch(testcontext.Context(), metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred(), "opening watch connection %d", i)
+			watchers = append(watchers, w)
+		}
+
+		log.Step("creating an object every watcher must observe")
+		probeName := fmt.Sprintf("watch-probe-%d", time.Now().Unix())
+		_, err := kube.CoreV1().ConfigMaps(proxyNamespace).Create(testcontext.Context(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: probeName},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		failures := make(chan error, concurrentWatches)
+		for i, w := range watchers {
+			wg.Add(1)
+			go func(i int, w watch.Interface) {
+				defer wg.Done()
+				deadline := time.After(watchEventTimeout)
+				for {
+					select {
+					case event, ok := <-w.ResultChan():
+						if !ok {
+							failures <- fmt.Errorf("watch %d was dropped before delivering the probe event", i)
+							return
+						}
+						if configMap, ok := event.Object.(*corev1.ConfigMap); ok && configMap.Name == probeName {
+							return
+						}
+					case <-deadline:
+						failures <- fmt.Errorf("watch %d did not deliver the probe event within %s", i, watchEventTimeout)
+						return
+					}
+				}
+			}(i, w)
+		}
+		wg.Wait()
+		close(failures)
+
+		for failure := range failures {
+			Fail(failure.Error())
+		}
+	})
+
+	It("should serve many concurrent exec connections", func() {
+		kube := testcontext.KubeClient()
+		ensureProxyNamespace()
+
+		log.Step("starting an exec target pod")
+		_, err := kube.CoreV1().Pods(proxyNamespace).Create(testcontext.Context(), &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: execPod},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:    "shell",
+						Image:   "docker.io/library/busybox:1.31.1",
+						Command: []string{"sleep", "3600"},
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Eventually(func() (corev1.PodPhase, error) {
+			pod, err := kube.CoreV1().Pods(proxyNamespace).Get(testcontext.Context(), execPod, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			return pod.Status.Phase, nil
+		}, podReadyTimeout, statusPoll).Should(Equal(corev1.PodRunning))
+
+		config, err := k8s.Config(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("running %d concurrent execs through the proxy", concurrentExecs)
+		var wg sync.WaitGroup
+		failures := make(chan error, concurrentExecs)
+		for i := 0; i < concurrentExecs; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				marker := fmt.Sprintf("proxy-exec-ok-%d", i)
+
+				req := kube.CoreV1().RESTClient().Post().
+					Resource("pods").
+					Namespace(proxyNamespace).
+					Name(execPod).
+					SubResource("exec").
+					VersionedParams(&corev1.PodExecOptions{
+						Container: "shell",
+						Command:   []string{"echo", marker},
+						Stdout:    true,
+						Stderr:    true,
+					}, scheme.ParameterCodec)
+
+				executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+				if err != nil {
+					failures <- fmt.Errorf("exec %d: building executor: %v", i, err)
+					return
+				}
+
+				ctx, cancel := context.WithTimeout(testcontext.Context(), execTimeout)
+				defer cancel()
+
+				var stdout, stderr strings.Builder
+				if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+					Stdout: &stdout,
+					Stderr: &stderr,
+				}); err != nil {
+					failures <- fmt.Errorf("exec %d: %v (stderr: %s)", i, err, stderr.String())
+					return
+				}
+				if !strings.Contains(stdout.String(), marker) {
+					failures <- fmt.Errorf("exec %d: expected output %q, got %q", i, marker, stdout.String())
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(failures)
+
+		for failure := range failures {
+			Fail(failure.Error())
+		}
+	})
+
+	It("should clean up the proxy probe workloads", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), proxyNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,218 @@
+package proxy
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// These specs register a throwaway CRD and drive custom resources through
+// the proxy. CRD types exercise aggregation and discovery paths that the
+// built-in-type specs never touch, and both have broken independently.
+const (
+	crdGroup     = "e2e.containership.io"
+	crdVersion   = "v1"
+	crdPlural    = "widgets"
+	crdKind      = "Widget"
+	crdName      = crdPlural + "." + crdGroup
+	crdNamespace = "e2e-proxy-crd"
+
+	crdEstablishTimeout = 2 * time.Minute
+	crdDiscoveryTimeout = 2 * time.Minute
+	crdWatchTimeout     = 1 * time.Minute
+	crdPoll             = 5 * time.Second
+)
+
+var widgetGVR = schema.GroupVersionResource{
+	Group:    crdGroup,
+	Version:  crdVersion,
+	Resource: crdPlural,
+}
+
+// widgetCRD returns the definition of the throwaway Widget type: namespaced,
+// one free-form spec field, no conversion or subresources.
+func widgetCRD() *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: crdName},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: crdGroup,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   crdPlural,
+				Singular: "widget",
+				Kind:     crdKind,
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    crdVersion,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"message": {Type: "string"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// widget returns an unstructured Widget with the given name.
+func widget(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": crdGroup + "/" + crdVersion,
+			"kind":       crdKind,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"message": "created through the proxy",
+			},
+		},
+	}
+}
+
+var _ = Describe("Custom resource lifecycle through the proxy", func() {
+	var (
+		extClient apiextensionsclient.Interface
+		dynClient dynamic.Interface
+	)
+
+	BeforeEach(func() {
+		config, err := k8s.Config(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		extClient, err = apiextensionsclient.NewForConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		dynClient, err = dynamic.NewForConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should register the CRD and surface it in discovery", func() {
+		_, err := extClient.ApiextensionsV1().CustomResourceDefinitions().Create(
+			testcontext.Context(), widgetCRD(), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the CRD to be established")
+		Eventually(func() (bool, error) {
+			crd, err := extClient.ApiextensionsV1().CustomResourceDefinitions().Get(
+				testcontext.Context(), crdName, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			for _, cond := range crd.Status.Conditions {
+				if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, crdEstablishTimeout, crdPoll).Should(BeTrue(), "CRD %s never became established", crdName)
+
+		log.Step("waiting for discovery to list the new resource")
+		Eventually(func() (bool, error) {
+			resources, err := testcontext.KubeClient().Discovery().ServerResourcesForGroupVersion(
+				crdGroup + "/" + crdVersion)
+			if err != nil {
+				// Discovery 404s until the aggregator picks up the group.
+				if apierrors.IsNotFound(err) {
+					return false, nil
+				}
+				return false, err
+			}
+			for _, resource := range resources.APIResources {
+				if resource.Name == crdPlural {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, crdDiscoveryTimeout, crdPoll).Should(BeTrue(),
+			"%s never appeared in discovery for %s/%s", crdPlural, crdGroup, crdVersion)
+	})
+
+	It("should create, list, and watch custom resources", func() {
+		kube := testcontext.KubeClient()
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: crdNamespace},
+		}
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		widgets := dynClient.Resource(widgetGVR).Namespace(crdNamespace)
+
+		log.Step("starting a watch before creating anything")
+		watcher, err := widgets.Watch(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		defer watcher.Stop()
+
+		log.Step("creating custom resources")
+		for i := 0; i < 3; i++ {
+			_, err := widgets.Create(testcontext.Context(), widget(fmt.Sprintf("widget-%d", i)), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		log.Step("listing them back")
+		list, err := widgets.List(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Items).To(HaveLen(3))
+
+		log.Step("receiving the create events on the watch")
+		received := 0
+		deadline := time.After(crdWatchTimeout)
+		for received < 3 {
+			select {
+			case event, ok := <-watcher.ResultChan():
+				Expect(ok).To(BeTrue(), "watch channel closed after %d of 3 events", received)
+				Expect(string(event.Type)).To(Equal("ADDED"))
+				received++
+			case <-deadline:
+				Fail(fmt.Sprintf("timed out after receiving %d of 3 watch events", received))
+			}
+		}
+	})
+
+	It("should delete the CRD and remove it from discovery", func() {
+		err := extClient.ApiextensionsV1().CustomResourceDefinitions().Delete(
+			testcontext.Context(), crdName, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the group to disappear from discovery")
+		Eventually(func() bool {
+			_, err := testcontext.KubeClient().Discovery().ServerResourcesForGroupVersion(
+				crdGroup + "/" + crdVersion)
+			return apierrors.IsNotFound(err)
+		}, crdDiscoveryTimeout, crdPoll).Should(BeTrue(),
+			"%s/%s still discoverable after CRD deletion", crdGroup, crdVersion)
+
+		err = testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), crdNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	payloadNamespace = "e2e-proxy-payload"
+
+	// largeObjectBytes sits just under the apiserver's ~1MiB object limit,
+	// well past any proxy buffer sizes that would truncate a response.
+	largeObjectBytes = 900 * 1024
+
+	// smallObjectCount is how many objects the large-list spec creates;
+	// listing them all in one response has previously tripped proxy
+	// buffering limits.
+	smallObjectCount = 2000
+	// createWorkers bounds concurrent creates so populating the namespace
+	// doesn't itself hammer the proxy.
+	createWorkers = 20
+
+	largeListTimeout = 2 * time.Minute
+)
+
+var _ = Describe("Proxy payload limits", func() {
+	It("should round-trip an object near the size limit", func() {
+		kube := testcontext.KubeClient()
+
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: payloadNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		payload := strings.Repeat("0123456789abcdef", largeObjectBytes/16)
+		sum := sha256.Sum256([]byte(payload))
+
+		log.Step("creating a %d byte ConfigMap through the proxy", len(payload))
+		_, err = kube.CoreV1().ConfigMaps(payloadNamespace).Create(testcontext.Context(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "large-object"},
+			Data:       map[string]string{"payload": payload},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("reading it back and verifying it was not truncated or corrupted")
+		fetched, err := kube.CoreV1().ConfigMaps(payloadNamespace).Get(
+			testcontext.Context(), "large-object", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fetched.Data["payload"]).To(HaveLen(len(payload)),
+			"large object came back truncated through the proxy")
+
+		fetchedSum := sha256.Sum256([]byte(fetched.Data["payload"]))
+		Expect(hex.EncodeToString(fetchedSum[:])).To(Equal(hex.EncodeToString(sum[:])),
+			"large object came back corrupted through the proxy")
+	})
+
+	It("should list thousands of objects without truncation", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("creating %d small objects", smallObjectCount)
+		names := make(chan int, smallObjectCount)
+		for i := 0; i < smallObjectCount; i++ {
+			names <- i
+		}
+		close(names)
+
+		var wg sync.WaitGroup
+		failures := make(chan error, smallObjectCount)
+		for worker := 0; worker < createWorkers; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range names {
+					_, err := kube.CoreV1().ConfigMaps(payloadNamespace).Create(testcontext.Context(), &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   fmt.Sprintf("small-%04d", i),
+							Labels: map[string]string{"e2e.containership.io/proxy-payload": "small"},
+						},
+						Data: map[string]string{"index": fmt.Sprintf("%d", i)},
+					}, metav1.CreateOptions{})
+					if err != nil && !apierrors.IsAlreadyExists(err) {
+						failures <- fmt.Errorf("creating object %d: %v", i, err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(failures)
+		for failure := range failures {
+			Fail(failure.Error())
+		}
+
+		log.Step("listing them in a single response through the proxy")
+		start := time.Now()
+		list, err := kube.CoreV1().ConfigMaps(payloadNamespace).List(testcontext.Context(), metav1.ListOptions{
+			LabelSelector: "e2e.containership.io/proxy-payload=small",
+		})
+		Expect(err).NotTo(HaveOccurred(), "large list failed through the proxy")
+		Expect(time.Since(start)).To(BeNumerically("<", largeListTimeout),
+			"large list took unreasonably long through the proxy")
+		Expect(list.Items).To(HaveLen(smallObjectCount),
+			"large list came back truncated through the proxy")
+	})
+
+	It("should clean up the payload namespace", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), payloadNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
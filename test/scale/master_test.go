@@ -0,0 +1,199 @@
+package scale
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/util"
+)
+
+// Master scaling resizes etcd and the control plane under the suite's
+// feet, which not every provider or template supports and which is too
+// disruptive to spring on a shared cluster by default, so the specs are
+// opt-in.
+var masterScale = flag.Bool("master-scale", false,
+	"run the master pool scaling specs (1->3->1); requires a cluster whose master pool starts at 1")
+
+const (
+	// apiOutageTolerance is the longest continuous API server outage
+	// accepted while masters scale; brief blips during leader changes are
+	// expected, a sustained outage means the control plane fell over.
+	apiOutageTolerance = 2 * time.Minute
+
+	masterPoll     = 15 * time.Second
+	registerWindow = 5 * time.Minute
+)
+
+var masterPoolID string
+
+// findMasterPool returns the cluster's master pool.
+func findMasterPool() *cloud.NodePool {
+	pools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+	Expect(err).NotTo(HaveOccurred())
+
+	for i := range pools {
+		if pools[i].IsMaster() {
+			return &pools[i]
+		}
+	}
+	return nil
+}
+
+// scaleMastersTo scales the master pool to the target count and waits for
+// it to return to running, sampling API server availability the whole way
+// and failing on a sustained outage.
+func scaleMastersTo(target int32) {
+	client := testcontext.CloudClient()
+	kube := testcontext.KubeClient()
+
+	Expect(util.RetryCloudCall(func() error {
+		return client.ScaleNodePool(testcontext.ClusterID(), masterPoolID, target)
+	})).To(Succeed())
+
+	deadline := time.Now().Add(constants.ScaleTimeout)
+	var outageStart time.Time
+	var longestOutage time.Duration
+	for {
+		if _, err := kube.Discovery().ServerVersion(); err != nil {
+			if outageStart.IsZero() {
+				outageStart = time.Now()
+			}
+			if outage := time.Since(outageStart); outage > longestOutage {
+				longestOutage = outage
+			}
+			log.Warnf("API server unavailable during master scale: %v", err)
+		} else {
+			outageStart = time.Time{}
+		}
+		Expect(longestOutage).To(BeNumerically("<=", apiOutageTolerance),
+			"API server was continuously unavailable for over %s while scaling masters", apiOutageTolerance)
+
+		pool, err := client.GetNodePool(testcontext.ClusterID(), masterPoolID)
+		if err == nil && pool.Status.Type == cloud.NodePoolStatusRunning && pool.Count == target {
+			break
+		}
+		Expect(time.Now()).To(BeTemporally("<", deadline),
+			"master pool %s never reached %d running nodes within %s", masterPoolID, target, constants.ScaleTimeout)
+
+		time.Sleep(masterPoll)
+	}
+
+	log.Infof("master pool %s is running at %d nodes (longest API outage: %s)",
+		masterPoolID, target, longestOutage)
+}
+
+// readyMasterNodes returns the Ready Kubernetes nodes belonging to the
+// master pool.
+func readyMasterNodes() ([]corev1.Node, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, masterPoolID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []corev1.Node
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready = append(ready, node)
+				break
+			}
+		}
+	}
+	return ready, nil
+}
+
+// expectMastersRegistered waits for exactly count Ready control-plane
+// nodes and, where etcd runs as visible static pods, a matching Ready
+// etcd member per master.
+func expectMastersRegistered(count int) {
+	Eventually(func() (int, error) {
+		ready, err := readyMasterNodes()
+		return len(ready), err
+	}, registerWindow, masterPoll).Should(Equal(count),
+		"expected %d Ready control-plane nodes in Kubernetes", count)
+
+	pods, err := testcontext.KubeClient().CoreV1().Pods(metav1.NamespaceSystem).List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: "component=etcd",
+	})
+	Expect(err).NotTo(HaveOccurred())
+	if len(pods.Items) == 0 {
+		log.Infof("no etcd pods visible in %s; skipping etcd membership check", metav1.NamespaceSystem)
+		return
+	}
+
+	Eventually(func() (int, error) {
+		pods, err := testcontext.KubeClient().CoreV1().Pods(metav1.NamespaceSystem).List(testcontext.Context(), metav1.ListOptions{
+			LabelSelector: "component=etcd",
+		})
+		if err != nil {
+			return 0, err
+		}
+		ready := 0
+		for _, pod := range pods.Items {
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+					ready++
+					break
+				}
+			}
+		}
+		return ready, nil
+	}, registerWindow, masterPoll).Should(Equal(count),
+		"expected %d Ready etcd members, one per master", count)
+}
+
+var _ = Describe("Master node pool scaling", func() {
+	It("should find a single-master pool to scale", func() {
+		if !*masterScale {
+			Skip("master scaling specs are opt-in; pass --master-scale to run them")
+		}
+
+		pool := findMasterPool()
+		Expect(pool).NotTo(BeNil(), "cluster %s has no master pool", testcontext.ClusterID())
+		if pool.Count != 1 {
+			Skip(fmt.Sprintf("master pool %s has %d nodes; the 1->3->1 cycle needs a single master", pool.ID, pool.Count))
+		}
+		masterPoolID = pool.ID
+	})
+
+	It("should scale the master pool from 1 to 3", func() {
+		if masterPoolID == "" {
+			Skip("no eligible master pool")
+		}
+
+		log.Step("scaling master pool %s from 1 to 3 nodes", masterPoolID)
+		start := time.Now()
+		scaleMastersTo(3)
+		metrics.Record("master_scale_up_seconds", time.Since(start))
+
+		expectMastersRegistered(3)
+	})
+
+	It("should scale the master pool back from 3 to 1", func() {
+		if masterPoolID == "" {
+			Skip("no eligible master pool")
+		}
+
+		log.Step("scaling master pool %s from 3 back to 1 node", masterPoolID)
+		start := time.Now()
+		scaleMastersTo(1)
+		metrics.Record("master_scale_down_seconds", time.Since(start))
+
+		expectMastersRegistered(1)
+	})
+})
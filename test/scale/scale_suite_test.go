@@ -0,0 +1,158 @@
+package scale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/util"
+)
+
+// The scale suite scales a worker pool of an existing cluster up by one
+// node, verifies that the new capacity is actually schedulable by landing a
+// spread workload on it, and scales back down afterwards.
+var (
+	workerPoolID  string
+	originalCount int32
+	scaleStart    time.Time
+)
+
+func TestScale(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+
+	if runmode.ReadOnly() {
+		t.Skip("scale suite mutates the cluster; skipping in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("scale")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("scale")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("scale")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Scale Suite", reporting.Reporters("scale"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the scale suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+
+	nodePools, err := client.ListNodePools(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+
+	var workerPool *cloud.NodePool
+	for i := range nodePools {
+		if !nodePools[i].IsMaster() {
+			workerPool = &nodePools[i]
+			break
+		}
+	}
+	Expect(workerPool).NotTo(BeNil(), "cluster %s has no worker pools to scale", clusterID)
+
+	workerPoolID = workerPool.ID
+	originalCount = workerPool.Count
+	scaleStart = time.Now()
+
+	log.Step("scaling worker pool %s from %d to %d nodes", workerPoolID, originalCount, originalCount+1)
+	Expect(util.RetryCloudCall(func() error {
+		return client.ScaleNodePool(clusterID, workerPoolID, originalCount+1)
+	})).To(Succeed())
+	Expect(client.WaitForNodePoolRunning(clusterID, workerPoolID, constants.ScaleTimeout)).To(Succeed())
+	metrics.Record("scale_up_seconds", time.Since(scaleStart))
+
+	kubeClient, err := k8s.New(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetKubeClient(kubeClient)
+
+	return []byte(fmt.Sprintf("%s,%d,%d", workerPoolID, originalCount, scaleStart.Unix()))
+}, func(data []byte) {
+	Expect(testcontext.EnsureBootstrapped("scale")).To(Succeed())
+
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(3))
+	workerPoolID = parts[0]
+
+	count, err := strconv.ParseInt(parts[1], 10, 32)
+	Expect(err).NotTo(HaveOccurred())
+	originalCount = int32(count)
+
+	startUnix, err := strconv.ParseInt(parts[2], 10, 64)
+	Expect(err).NotTo(HaveOccurred())
+	scaleStart = time.Unix(startUnix, 0)
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	defer func() {
+		Expect(metrics.Flush()).To(Succeed())
+	}()
+
+	// Scale back down so repeated runs against the same cluster don't grow
+	// it without bound.
+	log.Step("scaling worker pool %s back to %d nodes", workerPoolID, originalCount)
+	client := testcontext.CloudClient()
+	Expect(util.RetryCloudCall(func() error {
+		return client.ScaleNodePool(testcontext.ClusterID(), workerPoolID, originalCount)
+	})).To(Succeed())
+	Expect(client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)).To(Succeed())
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,230 @@
+package scale
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	rebalanceNamespace  = "e2e-scale-rebalance"
+	rebalanceDeployment = "rebalance"
+	workloadTimeout     = 5 * time.Minute
+	workloadPoll        = 5 * time.Second
+
+	// The pull-timing check measures cold pulls of a reference image on the
+	// new node to catch registry mirror/cache regressions in node images.
+	pullTimingNamespace = "e2e-scale-pull-timing"
+	pullTimingPodName   = "pull-timing"
+	pullTimingImage     = "docker.io/library/alpine:3.10"
+	imagePullTimeout    = 3 * time.Minute
+)
+
+var _ = Describe("Post-scale rebalance", func() {
+	It("should report a node count matching the pool counts", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		var expected int32
+		for _, nodePool := range nodePools {
+			expected += nodePool.Count
+		}
+
+		Eventually(func() (int, error) {
+			nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return len(nodes.Items), nil
+		}, workloadTimeout, workloadPoll).Should(Equal(int(expected)),
+			"Kubernetes node count should match the sum of pool counts")
+	})
+
+	It("should schedule a spread workload onto the new node", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("finding the nodes added by the scale-up")
+		nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		newNodes := map[string]bool{}
+		for _, node := range nodes.Items {
+			if node.CreationTimestamp.Time.After(scaleStart) {
+				newNodes[node.Name] = true
+			}
+		}
+		Expect(newNodes).NotTo(BeEmpty(), "no Kubernetes nodes joined after the scale started")
+
+		log.Step("deploying a hostname-spread workload sized to the worker count")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: rebalanceNamespace},
+		}
+		_, err = kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		defer func() {
+			err := kube.CoreV1().Namespaces().Delete(testcontext.Context(), rebalanceNamespace, metav1.DeleteOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		replicas := originalCount + 1
+		_, err = kube.AppsV1().Deployments(rebalanceNamespace).Create(testcontext.Context(), rebalanceWorkload(replicas), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for all replicas to be running")
+		Eventually(func() (int32, error) {
+			deployment, err := kube.AppsV1().Deployments(rebalanceNamespace).Get(testcontext.Context(), rebalanceDeployment, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, workloadTimeout, workloadPoll).Should(Equal(replicas))
+
+		log.Step("verifying at least one replica landed on a new node")
+		pods, err := kube.CoreV1().Pods(rebalanceNamespace).List(testcontext.Context(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("app=%s", rebalanceDeployment),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		onNewNode := 0
+		for _, pod := range pods.Items {
+			if newNodes[pod.Spec.NodeName] {
+				onNewNode++
+			}
+		}
+		Expect(onNewNode).To(BeNumerically(">", 0),
+			"no replica was scheduled onto the new capacity (%d new nodes, %d pods)", len(newNodes), len(pods.Items))
+	})
+
+	It("should pull a reference image on the new node within the expected time", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("finding a node added by the scale-up")
+		nodes, err := kube.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var newNode string
+		for _, node := range nodes.Items {
+			if node.CreationTimestamp.Time.After(scaleStart) {
+				newNode = node.Name
+				break
+			}
+		}
+		Expect(newNode).NotTo(BeEmpty(), "no Kubernetes nodes joined after the scale started")
+
+		log.Step("pinning a pull-timing pod to node %s", newNode)
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: pullTimingNamespace},
+		}
+		_, err = kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		defer func() {
+			err := kube.CoreV1().Namespaces().Delete(testcontext.Context(), pullTimingNamespace, metav1.DeleteOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		pullStart := time.Now()
+		_, err = kube.CoreV1().Pods(pullTimingNamespace).Create(testcontext.Context(), pullTimingPod(newNode), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("waiting for the reference image to be pulled and the pod to run")
+		Eventually(func() (corev1.PodPhase, error) {
+			pod, err := kube.CoreV1().Pods(pullTimingNamespace).Get(testcontext.Context(), pullTimingPodName, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			return pod.Status.Phase, nil
+		}, imagePullTimeout, workloadPoll).Should(Equal(corev1.PodRunning))
+
+		pullSeconds := time.Since(pullStart)
+		metrics.Record("image_pull_seconds", pullSeconds)
+		Expect(pullSeconds).To(BeNumerically("<", imagePullTimeout),
+			"reference image pull took %s on node %s", pullSeconds, newNode)
+	})
+
+	It("should emit an audit event for the scale operation", func() {
+		events, err := testcontext.CloudClient().ListEvents()
+		if cloud.IsNotFound(err) {
+			Skip("events API not available in this environment")
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		event := cloud.FindEvent(events, cloud.EventNodePoolScaled, workerPoolID)
+		Expect(event).NotTo(BeNil(), "no %s event for node pool %s", cloud.EventNodePoolScaled, workerPoolID)
+		Expect(event.ActorID).NotTo(BeEmpty(), "audit event is not attributable to an actor")
+	})
+})
+
+// pullTimingPod returns a pod pinned to the given node that always pulls
+// the reference image, so its time-to-running measures registry mirror and
+// cache behavior of the node image rather than a warm local cache.
+func pullTimingPod(nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pullTimingPodName,
+		},
+		Spec: corev1.PodSpec{
+			NodeName:      nodeName,
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:            "reference",
+					Image:           pullTimingImage,
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"sleep", "3600"},
+				},
+			},
+		},
+	}
+}
+
+// rebalanceWorkload returns a pause deployment spread one-per-node across
+// the workers, so a successful rollout proves the new node is schedulable.
+func rebalanceWorkload(replicas int32) *appsv1.Deployment {
+	labels := map[string]string{"app": rebalanceDeployment}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   rebalanceDeployment,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+						{
+							MaxSkew:           1,
+							TopologyKey:       "kubernetes.io/hostname",
+							WhenUnsatisfiable: corev1.DoNotSchedule,
+							LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "pause",
+							Image: "k8s.gcr.io/pause:3.2",
+						},
+					},
+				},
+			},
+		},
+	}
+}
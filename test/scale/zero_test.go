@@ -0,0 +1,151 @@
+package scale
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/metrics"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/util"
+)
+
+const (
+	zeroNamespace  = "e2e-scale-zero"
+	zeroDeployment = "displaced"
+
+	zeroStatusPoll = 10 * time.Second
+	// nodeRemovalTimeout bounds how long after the pool reports zero the
+	// Kubernetes nodes may linger.
+	nodeRemovalTimeout  = 10 * time.Minute
+	zeroWorkloadTimeout = 5 * time.Minute
+)
+
+// poolNodeCount counts the Kubernetes nodes belonging to the worker pool
+// under test.
+func poolNodeCount() (int, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(nodes.Items), nil
+}
+
+// displacedReadyReplicas returns the ready replica count of the displaced
+// deployment.
+func displacedReadyReplicas() (int32, error) {
+	deployment, err := testcontext.KubeClient().AppsV1().Deployments(zeroNamespace).Get(
+		testcontext.Context(), zeroDeployment, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return deployment.Status.ReadyReplicas, nil
+}
+
+// The scale-to-zero specs exercise the zero-count provisioning path, which
+// has bitten us before and is otherwise untested: the pool must drain
+// cleanly, its nodes must leave Kubernetes, and scaling back up must
+// restore schedulability.
+var _ = Describe("Scaling a worker pool to zero", func() {
+	It("should remove the pool's Kubernetes nodes", func() {
+		client := testcontext.CloudClient()
+		kube := testcontext.KubeClient()
+
+		log.Step("deploying a workload onto the pool so displacement is observable")
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: zeroNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		labels := map[string]string{"app": zeroDeployment}
+		replicas := int32(2)
+		_, err = kube.AppsV1().Deployments(zeroNamespace).Create(testcontext.Context(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: zeroDeployment, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						NodeSelector: map[string]string{
+							inventory.NodePoolIDLabel: workerPoolID,
+						},
+						Containers: []corev1.Container{
+							{
+								Name:  "pause",
+								Image: "k8s.gcr.io/pause:3.2",
+							},
+						},
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(displacedReadyReplicas, zeroWorkloadTimeout, zeroStatusPoll).Should(Equal(replicas))
+
+		log.Step("scaling worker pool %s to zero", workerPoolID)
+		Expect(util.RetryCloudCall(func() error {
+			return client.ScaleNodePool(testcontext.ClusterID(), workerPoolID, 0)
+		})).To(Succeed())
+		Expect(client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)).To(Succeed())
+
+		Eventually(poolNodeCount, nodeRemovalTimeout, zeroStatusPoll).Should(BeZero(),
+			"Kubernetes nodes for pool %s were not removed after scaling to zero", workerPoolID)
+	})
+
+	It("should leave displaced workloads pending rather than failed", func() {
+		Eventually(displacedReadyReplicas, zeroWorkloadTimeout, zeroStatusPoll).Should(BeZero())
+
+		pods, err := testcontext.KubeClient().CoreV1().Pods(zeroNamespace).List(testcontext.Context(), metav1.ListOptions{
+			LabelSelector: "app=" + zeroDeployment,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods.Items).NotTo(BeEmpty(),
+			"displaced workload has no pods at all; expected pending replacements")
+		for _, pod := range pods.Items {
+			Expect(pod.Status.Phase).To(Or(Equal(corev1.PodPending), Equal(corev1.PodRunning)),
+				"pod %s is %s; displaced pods should be pending replacements", pod.Name, pod.Status.Phase)
+		}
+	})
+
+	It("should restore capacity and reschedule workloads on scale-up", func() {
+		client := testcontext.CloudClient()
+
+		// The suite scaled the pool to originalCount+1 at setup; return to
+		// that so the suite teardown's scale-down still applies cleanly.
+		target := originalCount + 1
+		log.Step("scaling worker pool %s from zero back to %d", workerPoolID, target)
+		fromZeroStart := time.Now()
+		Expect(util.RetryCloudCall(func() error {
+			return client.ScaleNodePool(testcontext.ClusterID(), workerPoolID, target)
+		})).To(Succeed())
+		Expect(client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)).To(Succeed())
+
+		Eventually(poolNodeCount, nodeRemovalTimeout, zeroStatusPoll).Should(Equal(int(target)),
+			"Kubernetes nodes for pool %s did not register after scaling up from zero", workerPoolID)
+		metrics.Record("scale_from_zero_seconds", time.Since(fromZeroStart))
+
+		log.Step("verifying the displaced workload reschedules")
+		Eventually(displacedReadyReplicas, zeroWorkloadTimeout, zeroStatusPoll).Should(Equal(int32(2)),
+			"displaced workload did not reschedule after scaling up from zero")
+
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), zeroNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,163 @@
+package selfheal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/diagnostics"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var (
+	// workerPoolID is the pool the suite terminates a node in.
+	workerPoolID string
+	// targetNode is the Kubernetes name of the node to be terminated.
+	targetNode string
+	// targetNodeID is the provision API's ID for the same node.
+	targetNodeID string
+)
+
+// The self-healing suite terminates one worker node through the provision
+// API and verifies Containership replaces it: the pool transitions through
+// updating, returns to its target count, and the replacement node
+// registers as Ready in Kubernetes.
+func TestSelfHeal(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("self-healing suite terminates a node; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("selfheal")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("selfheal")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("selfheal")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Self-Healing Suite", reporting.Reporters("selfheal"))
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	clusterID := testcontext.ResolveClusterID()
+	Expect(clusterID).NotTo(BeEmpty(), "--cluster-id or %s must be set for the self-healing suite", constants.ClusterIDEnvVar)
+	testcontext.SetClusterID(clusterID)
+
+	nodePools, err := client.ListNodePools(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	for i := range nodePools {
+		if !nodePools[i].IsMaster() {
+			workerPoolID = nodePools[i].ID
+			break
+		}
+	}
+	Expect(workerPoolID).NotTo(BeEmpty(), "cluster %s has no worker pools", clusterID)
+
+	kubeClient, err := k8s.New(clusterID)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetKubeClient(kubeClient)
+
+	// Pick the first node of the worker pool as the victim and resolve its
+	// cloud-side ID through the instance ID label, since the provision API
+	// knows nothing about Kubernetes node names.
+	nodes, err := kubeClient.CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nodes.Items).NotTo(BeEmpty(), "worker pool %s has no Kubernetes nodes", workerPoolID)
+	targetNode = nodes.Items[0].Name
+	instanceID := nodes.Items[0].Labels[inventory.InstanceIDLabel]
+	Expect(instanceID).NotTo(BeEmpty(), "node %s has no instance ID label", targetNode)
+
+	cloudNodes, err := client.ListNodes(clusterID, workerPoolID)
+	Expect(err).NotTo(HaveOccurred())
+	for i := range cloudNodes {
+		if cloudNodes[i].InstanceID == instanceID {
+			targetNodeID = cloudNodes[i].ID
+			break
+		}
+	}
+	Expect(targetNodeID).NotTo(BeEmpty(),
+		"instance %s (node %s) is not tracked by the provision API", instanceID, targetNode)
+
+	log.Infof("terminating %s (cloud node %s, pool %s) to exercise self-healing",
+		targetNode, targetNodeID, workerPoolID)
+
+	return []byte(fmt.Sprintf("%s,%s,%s", workerPoolID, targetNode, targetNodeID))
+}, func(data []byte) {
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(3))
+
+	Expect(testcontext.EnsureBootstrapped("self-healing")).To(Succeed())
+	workerPoolID = parts[0]
+	targetNode = parts[1]
+	targetNodeID = parts[2]
+})
+
+// On any spec failure, dump cloud- and Kubernetes-side diagnostics (state,
+// events, system logs) into the artifacts directory.
+var _ = JustAfterEach(func() {
+	if CurrentGinkgoTestDescription().Failed {
+		//nolint:errcheck - a partial dump is better than failing the dump
+		diagnostics.Collect()
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
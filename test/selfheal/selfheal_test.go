@@ -0,0 +1,153 @@
+package selfheal
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// updatingDeadline bounds how long the pool may take to leave running
+	// after a node is terminated. The pool then holds updating for the
+	// minutes the replacement takes to provision, so the poll won't miss
+	// the transition.
+	updatingDeadline = 5 * time.Minute
+
+	// registerTimeout is how long the replacement node may take to appear
+	// in Kubernetes and go Ready after the pool reports running again.
+	registerTimeout = 10 * time.Minute
+
+	statusPoll = constants.DefaultPollInterval
+)
+
+// poolTargetCount is the pool's node count before the termination, captured
+// so later specs can verify self-healing restored it.
+var poolTargetCount int32
+
+// terminatedAt is when the node was terminated, for reporting how long the
+// replacement took end to end.
+var terminatedAt time.Time
+
+var _ = Describe("Node self-healing", func() {
+	It("should terminate a worker node through the provision API", func() {
+		client := testcontext.CloudClient()
+
+		pool, err := client.GetNodePool(testcontext.ClusterID(), workerPoolID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pool.Status.Type).To(Equal(cloud.NodePoolStatusRunning),
+			"pool %s must be healthy before a node is terminated", workerPoolID)
+		poolTargetCount = pool.Count
+
+		log.Step("terminating node %s (cloud node %s)", targetNode, targetNodeID)
+		terminatedAt = time.Now()
+		err = client.DeleteNode(testcontext.ClusterID(), workerPoolID, targetNodeID)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should transition the pool through updating", func() {
+		Eventually(poolStatus, updatingDeadline, statusPoll).Should(Equal(cloud.NodePoolStatusUpdating),
+			"pool %s never reported updating after node %s was terminated", workerPoolID, targetNodeID)
+	})
+
+	It("should restore the pool to its target count", func() {
+		client := testcontext.CloudClient()
+
+		log.Step("waiting for pool %s to return to running", workerPoolID)
+		err := client.WaitForNodePoolRunning(testcontext.ClusterID(), workerPoolID, constants.ScaleTimeout)
+		Expect(err).NotTo(HaveOccurred())
+
+		nodes, err := client.ListNodes(testcontext.ClusterID(), workerPoolID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(HaveLen(int(poolTargetCount)),
+			"pool %s did not return to its target count", workerPoolID)
+
+		for _, node := range nodes {
+			Expect(node.ID).NotTo(Equal(targetNodeID),
+				"terminated node %s is still tracked by the provision API", targetNodeID)
+		}
+	})
+
+	It("should register a Ready replacement node in Kubernetes", func() {
+		log.Step("waiting for the replacement node to register and go Ready")
+		Eventually(func() (int, error) {
+			nodes, err := kubePoolNodes()
+			if err != nil {
+				return 0, err
+			}
+
+			ready := 0
+			for i := range nodes {
+				// The terminated node's object may linger until the node sync
+				// controller reaps it; it must not count toward the target.
+				if nodes[i].Name == targetNode || !nodeIsReady(&nodes[i]) {
+					continue
+				}
+				ready++
+			}
+			return ready, nil
+		}, registerTimeout, statusPoll).Should(Equal(int(poolTargetCount)),
+			"pool %s never regained %d Ready node(s) excluding terminated node %s",
+			workerPoolID, poolTargetCount, targetNode)
+
+		log.Infof("node %s replaced and Ready after %s",
+			targetNode, time.Since(terminatedAt).Round(time.Second))
+	})
+
+	It("should remove the terminated node from Kubernetes", func() {
+		Eventually(func() (bool, error) {
+			nodes, err := kubePoolNodes()
+			if err != nil {
+				return false, err
+			}
+			for i := range nodes {
+				if nodes[i].Name == targetNode {
+					return false, nil
+				}
+			}
+			return true, nil
+		}, registerTimeout, statusPoll).Should(BeTrue(),
+			"terminated node %s was never removed from Kubernetes", targetNode)
+	})
+})
+
+// poolStatus returns the worker pool's current status type.
+func poolStatus() (string, error) {
+	pool, err := testcontext.CloudClient().GetNodePool(testcontext.ClusterID(), workerPoolID)
+	if err != nil {
+		return "", err
+	}
+	return pool.Status.Type, nil
+}
+
+// kubePoolNodes lists the Kubernetes nodes labeled as belonging to the
+// worker pool.
+func kubePoolNodes() ([]corev1.Node, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// nodeIsReady returns true if the node's Ready condition is True.
+func nodeIsReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package smoke
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The DNS specs verify cluster DNS end to end: the CoreDNS (or kube-dns)
+// deployment itself, cluster-internal names, headless services, and
+// external resolution through the upstream resolvers. They use their own
+// namespace so they run standalone with -ginkgo.focus="Cluster DNS" as
+// well as part of the full smoke suite.
+const (
+	dnsNamespace      = "e2e-dns"
+	dnsBackend        = "dns-backend"
+	dnsHeadlessSvc    = "dns-headless"
+	dnsExternalTarget = "example.com"
+
+	// kubeDNSLabel selects the cluster DNS pods; CoreDNS keeps the
+	// kube-dns label for compatibility.
+	kubeDNSLabel = "k8s-app=kube-dns"
+
+	dnsTimeout = 5 * time.Minute
+	dnsPoll    = 5 * time.Second
+)
+
+// dnsLookupPod returns a one-shot pod that retries the lookup for a few
+// minutes before giving up, since DNS caches and a cold CoreDNS can make
+// the first queries after provisioning fail spuriously.
+func dnsLookupPod(name, target string) *corev1.Pod {
+	pod := clientPod(name, []string{"sh", "-c", fmt.Sprintf(
+		"for i in $(seq 1 24); do nslookup %s && exit 0; sleep 5; done; exit 1", target)})
+	return pod
+}
+
+// waitForLookupSuccess waits for the named one-shot lookup pod in the DNS
+// namespace to succeed.
+func waitForLookupSuccess(name string) {
+	Eventually(func() (corev1.PodPhase, error) {
+		pod, err := testcontext.KubeClient().CoreV1().Pods(dnsNamespace).Get(testcontext.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pod.Status.Phase, nil
+	}, dnsTimeout, dnsPoll).Should(Equal(corev1.PodSucceeded), "lookup pod %s did not succeed", name)
+}
+
+var _ = Describe("Cluster DNS", func() {
+	It("should have every DNS pod ready", func() {
+		kube := testcontext.KubeClient()
+
+		Eventually(func() (string, error) {
+			pods, err := kube.CoreV1().Pods(metav1.NamespaceSystem).List(testcontext.Context(), metav1.ListOptions{
+				LabelSelector: kubeDNSLabel,
+			})
+			if err != nil {
+				return "", err
+			}
+			if len(pods.Items) == 0 {
+				return "no DNS pods found", nil
+			}
+
+			for _, pod := range pods.Items {
+				ready := false
+				for _, condition := range pod.Status.Conditions {
+					if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+						ready = true
+						break
+					}
+				}
+				if !ready {
+					return fmt.Sprintf("pod %s is not ready (%s)", pod.Name, pod.Status.Phase), nil
+				}
+			}
+			return "ready", nil
+		}, dnsTimeout, dnsPoll).Should(Equal("ready"),
+			"cluster DNS pods (%s in %s) never became ready", kubeDNSLabel, metav1.NamespaceSystem)
+	})
+
+	It("should resolve kubernetes.default", func() {
+		kube := testcontext.KubeClient()
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: dnsNamespace},
+		}
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		pod := dnsLookupPod("lookup-kubernetes", "kubernetes.default")
+		_, err = kube.CoreV1().Pods(dnsNamespace).Create(testcontext.Context(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForLookupSuccess("lookup-kubernetes")
+	})
+
+	It("should resolve a headless service", func() {
+		kube := testcontext.KubeClient()
+
+		// A headless service resolves straight to pod IPs, exercising the
+		// endpoints-to-records path that ClusterIP lookups skip.
+		replicas := int32(1)
+		labels := map[string]string{"app": dnsBackend}
+		_, err := kube.AppsV1().Deployments(dnsNamespace).Create(testcontext.Context(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: dnsBackend},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:    "backend",
+								Image:   clientImage,
+								Command: []string{"sleep", "3600"},
+							},
+						},
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = kube.CoreV1().Services(dnsNamespace).Create(testcontext.Context(), &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: dnsHeadlessSvc},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+				Selector:  labels,
+				Ports: []corev1.ServicePort{
+					{Port: 80},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		pod := dnsLookupPod("lookup-headless", fmt.Sprintf("%s.%s.svc", dnsHeadlessSvc, dnsNamespace))
+		_, err = kube.CoreV1().Pods(dnsNamespace).Create(testcontext.Context(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForLookupSuccess("lookup-headless")
+	})
+
+	It("should resolve an external name", func() {
+		kube := testcontext.KubeClient()
+
+		pod := dnsLookupPod("lookup-external", dnsExternalTarget)
+		_, err := kube.CoreV1().Pods(dnsNamespace).Create(testcontext.Context(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForLookupSuccess("lookup-external")
+	})
+
+	It("should clean up the DNS probe namespace", func() {
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(
+			testcontext.Context(), dnsNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
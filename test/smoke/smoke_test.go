@@ -0,0 +1,184 @@
+package smoke
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	smokeNamespace  = "e2e-smoke"
+	smokeDeployment = "nginx"
+	smokeService    = "nginx"
+	smokeReplicas   = 2
+
+	nginxImage   = "docker.io/library/nginx:1.17"
+	clientImage  = "docker.io/library/busybox:1.31.1"
+	serviceDNS   = smokeService + "." + smokeNamespace + ".svc"
+	smokeTimeout = 5 * time.Minute
+	smokePoll    = 5 * time.Second
+
+	teardownTimeout = 5 * time.Minute
+)
+
+// nginxWorkload returns the smoke deployment: plain nginx, no config, so a
+// 200 from it proves scheduling, image pulls, and pod networking all work.
+func nginxWorkload() *appsv1.Deployment {
+	replicas := int32(smokeReplicas)
+	labels := map[string]string{"app": smokeDeployment}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   smokeDeployment,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "nginx",
+							Image: nginxImage,
+							Ports: []corev1.ContainerPort{
+								{ContainerPort: 80},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// clientPod returns a one-shot busybox pod running the given command, for
+// driving checks from inside the cluster network.
+func clientPod(name string, command []string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "client",
+					Image:   clientImage,
+					Command: command,
+				},
+			},
+		},
+	}
+}
+
+// waitForPodSuccess waits for the named one-shot pod to succeed, failing
+// fast if it errors out instead.
+func waitForPodSuccess(name string) {
+	Eventually(func() (corev1.PodPhase, error) {
+		pod, err := testcontext.KubeClient().CoreV1().Pods(smokeNamespace).Get(testcontext.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pod.Status.Phase, nil
+	}, smokeTimeout, smokePoll).Should(Equal(corev1.PodSucceeded), "pod %s did not succeed", name)
+}
+
+var _ = Describe("Cluster smoke test", func() {
+	It("should deploy an nginx workload", func() {
+		kube := testcontext.KubeClient()
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: smokeNamespace},
+		}
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = kube.AppsV1().Deployments(smokeNamespace).Create(testcontext.Context(), nginxWorkload(), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() (int32, error) {
+			deployment, err := kube.AppsV1().Deployments(smokeNamespace).Get(testcontext.Context(), smokeDeployment, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, smokeTimeout, smokePoll).Should(Equal(int32(smokeReplicas)))
+	})
+
+	It("should expose the workload via a ClusterIP service with endpoints", func() {
+		kube := testcontext.KubeClient()
+
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: smokeService},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": smokeDeployment},
+				Ports: []corev1.ServicePort{
+					{Port: 80, TargetPort: intstr.FromInt(80)},
+				},
+			},
+		}
+		_, err := kube.CoreV1().Services(smokeNamespace).Create(testcontext.Context(), service, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() (int, error) {
+			endpoints, err := kube.CoreV1().Endpoints(smokeNamespace).Get(testcontext.Context(), smokeService, metav1.GetOptions{})
+			if err != nil {
+				return 0, err
+			}
+			ready := 0
+			for _, subset := range endpoints.Subsets {
+				ready += len(subset.Addresses)
+			}
+			return ready, nil
+		}, smokeTimeout, smokePoll).Should(Equal(smokeReplicas),
+			"service %s never got endpoints for all replicas", smokeService)
+	})
+
+	It("should reach the service from a client pod", func() {
+		kube := testcontext.KubeClient()
+
+		pod := clientPod("curl-client", []string{
+			"wget", "-q", "-O", "-", "-T", "10", fmt.Sprintf("http://%s/", serviceDNS),
+		})
+		_, err := kube.CoreV1().Pods(smokeNamespace).Create(testcontext.Context(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForPodSuccess("curl-client")
+	})
+
+	It("should resolve the service DNS name in-cluster", func() {
+		kube := testcontext.KubeClient()
+
+		pod := clientPod("dns-client", []string{"nslookup", serviceDNS})
+		_, err := kube.CoreV1().Pods(smokeNamespace).Create(testcontext.Context(), pod, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		waitForPodSuccess("dns-client")
+	})
+
+	It("should tear everything down cleanly", func() {
+		kube := testcontext.KubeClient()
+
+		err := kube.CoreV1().Namespaces().Delete(testcontext.Context(), smokeNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Wait the namespace fully out: finalizers stuck on smoke resources
+		// would strand every later suite that reuses this cluster.
+		Eventually(func() bool {
+			_, err := kube.CoreV1().Namespaces().Get(testcontext.Context(), smokeNamespace, metav1.GetOptions{})
+			return apierrors.IsNotFound(err)
+		}, teardownTimeout, smokePoll).Should(BeTrue(), "namespace %s never finished deleting", smokeNamespace)
+	})
+})
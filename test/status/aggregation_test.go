@@ -0,0 +1,79 @@
+package status
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/k8s"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// The aggregation specs verify the apiserver can reach aggregated API
+// servers running in the cluster (the metrics API being the usual one).
+// That apiserver-to-cluster network path is wired up at provisioning time
+// and differs per provider, so it breaks independently of everything else.
+const (
+	metricsAPIService = "v1beta1.metrics.k8s.io"
+	metricsNodesPath  = "/apis/metrics.k8s.io/v1beta1/nodes"
+
+	aggregationTimeout = 3 * time.Minute
+	aggregationPoll    = 10 * time.Second
+)
+
+var _ = Describe("API aggregation layer", func() {
+	var aggregator aggregatorclient.Interface
+
+	BeforeEach(func() {
+		config, err := k8s.Config(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		aggregator, err = aggregatorclient.NewForConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report every registered APIService as available", func() {
+		apiServices, err := aggregator.ApiregistrationV1().APIServices().List(testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apiServices.Items).NotTo(BeEmpty())
+
+		var unavailable []string
+		for _, apiService := range apiServices.Items {
+			for _, cond := range apiService.Status.Conditions {
+				if cond.Type == apiregistrationv1.Available && cond.Status != apiregistrationv1.ConditionTrue {
+					unavailable = append(unavailable, apiService.Name+": "+cond.Message)
+				}
+			}
+		}
+
+		if len(unavailable) > 0 {
+			//nolint:errcheck - artifact export is best-effort
+			artifacts.WriteJSON("unavailable-apiservices.json", unavailable)
+		}
+		Expect(unavailable).To(BeEmpty(), "APIServices are unavailable; the apiserver cannot reach their backing services")
+	})
+
+	It("should serve the metrics API through the aggregator", func() {
+		_, err := aggregator.ApiregistrationV1().APIServices().Get(testcontext.Context(), metricsAPIService, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			Skip("metrics-server is not installed on this cluster")
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		// Node metrics lag behind metrics-server startup, so poll rather
+		// than fail on the first empty window.
+		Eventually(func() error {
+			_, err := testcontext.KubeClient().Discovery().RESTClient().
+				Get().AbsPath(metricsNodesPath).DoRaw(testcontext.Context())
+			return err
+		}, aggregationTimeout, aggregationPoll).Should(Succeed(),
+			"the aggregated metrics API never answered through the apiserver")
+	})
+})
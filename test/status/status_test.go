@@ -0,0 +1,67 @@
+package status
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/integrity"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+var _ = Describe("Cluster status", func() {
+	It("should report the cluster as running", func() {
+		cluster, err := testcontext.CloudClient().GetCluster(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Status.Type).To(Equal(cloud.ClusterStatusRunning),
+			"cluster status: %s", cluster.Status.Message)
+	})
+
+	It("should report all node pools as running", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodePools).NotTo(BeEmpty())
+
+		for _, nodePool := range nodePools {
+			Expect(nodePool.Status.Type).To(Equal(cloud.NodePoolStatusRunning),
+				"node pool %s (%s) is not running", nodePool.Name, nodePool.ID)
+		}
+	})
+
+	It("should report a consistent Kubernetes version across node pools", func() {
+		nodePools, err := testcontext.CloudClient().ListNodePools(testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodePools).NotTo(BeEmpty())
+
+		version := nodePools[0].KubernetesVersion
+		for _, nodePool := range nodePools {
+			Expect(nodePool.KubernetesVersion).To(Equal(version),
+				"node pool %s is on %s, others on %s", nodePool.Name, nodePool.KubernetesVersion, version)
+		}
+	})
+
+	It("should have matching node inventories in the cloud and in Kubernetes", func() {
+		report, err := inventory.Diff(testcontext.CloudClient(), testcontext.KubeClient(), testcontext.ClusterID())
+		Expect(err).NotTo(HaveOccurred())
+
+		if !report.Empty() {
+			//nolint:errcheck - artifact export is best-effort
+			artifacts.WriteJSON("inventory-drift.json", report)
+		}
+		Expect(report.CloudOnly).To(BeEmpty(), "cloud nodes missing from Kubernetes")
+		Expect(report.KubernetesOnly).To(BeEmpty(), "Kubernetes nodes unknown to the provision API")
+	})
+
+	It("should have healthy containership.io annotations and finalizers", func() {
+		problems, err := integrity.Audit(testcontext.KubeClient())
+		Expect(err).NotTo(HaveOccurred())
+
+		if len(problems) > 0 {
+			//nolint:errcheck - artifact export is best-effort
+			artifacts.WriteJSON("integrity-problems.json", problems)
+		}
+		Expect(problems).To(BeEmpty(), "containership.io metadata audit found problems")
+	})
+})
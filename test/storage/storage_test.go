@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/providers"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	storageNamespace = "e2e-storage"
+	claimName        = "e2e-storage-claim"
+	writerPod        = "storage-writer"
+	readerPod        = "storage-reader"
+	storageImage     = "docker.io/library/busybox:1.31.1"
+
+	// sentinel is the data the writer persists and the reader verifies.
+	sentinel = "e2e-storage-sentinel"
+
+	initialSize  = "1Gi"
+	expandedSize = "2Gi"
+
+	// defaultClassAnnotation marks the cluster's default StorageClass.
+	defaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	provisionTimeout = 10 * time.Minute
+	expandTimeout    = 10 * time.Minute
+	releaseTimeout   = 10 * time.Minute
+	storagePoll      = 10 * time.Second
+)
+
+// Shared across the ordered specs below.
+var (
+	provider         string
+	defaultClassName string
+	allowExpansion   bool
+	volumeName       string
+)
+
+// volumePod returns a one-shot pod running the given shell command with the
+// claim mounted at /data.
+func volumePod(name, command string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "main",
+					Image:   storageImage,
+					Command: []string{"sh", "-c", command},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: claimName,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// waitForPodSuccess waits for the named one-shot pod to succeed, failing
+// early if it lands in a terminal failed phase.
+func waitForPodSuccess(name string) {
+	Eventually(func() (corev1.PodPhase, error) {
+		pod, err := testcontext.KubeClient().CoreV1().Pods(storageNamespace).Get(
+			testcontext.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return pod.Status.Phase, nil
+	}, provisionTimeout, storagePoll).Should(Equal(corev1.PodSucceeded), "pod %s did not succeed", name)
+}
+
+var _ = Describe("Persistent storage", func() {
+	It("should detect the provider and a default StorageClass", func() {
+		provider = providers.Detect(testcontext.Context(),
+			testcontext.CloudClient(), testcontext.KubeClient(), testcontext.ClusterID())
+		log.Infof("detected provider %q for cluster %s", provider, testcontext.ClusterID())
+
+		classes, err := testcontext.KubeClient().StorageV1().StorageClasses().List(
+			testcontext.Context(), metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, class := range classes.Items {
+			if class.Annotations[defaultClassAnnotation] == "true" {
+				defaultClassName = class.Name
+				allowExpansion = class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion
+				break
+			}
+		}
+		if defaultClassName == "" {
+			Skip(fmt.Sprintf("cluster on provider %q has no default StorageClass; nothing to exercise", provider))
+		}
+
+		log.Infof("default StorageClass is %s (expansion supported: %v)", defaultClassName, allowExpansion)
+		//nolint:errcheck - the detection record is informational
+		artifacts.WriteJSON("storage-provider.json", map[string]interface{}{
+			"provider":           provider,
+			"default_class":      defaultClassName,
+			"supports_expansion": allowExpansion,
+		})
+	})
+
+	It("should provision and mount a volume from the default StorageClass", func() {
+		if defaultClassName == "" {
+			Skip("no default StorageClass")
+		}
+		kube := testcontext.KubeClient()
+
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: storageNamespace},
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		log.Step("creating a %s claim against StorageClass %s", initialSize, defaultClassName)
+		_, err = kube.CoreV1().PersistentVolumeClaims(storageNamespace).Create(testcontext.Context(), &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: claimName},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(initialSize),
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The writer pod forces binding even under WaitForFirstConsumer
+		// and proves the volume actually mounts and accepts writes.
+		log.Step("writing data through pod %s", writerPod)
+		_, err = kube.CoreV1().Pods(storageNamespace).Create(testcontext.Context(),
+			volumePod(writerPod, fmt.Sprintf("echo %s > /data/sentinel && sync", sentinel)),
+			metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		waitForPodSuccess(writerPod)
+
+		claim, err := kube.CoreV1().PersistentVolumeClaims(storageNamespace).Get(
+			testcontext.Context(), claimName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claim.Status.Phase).To(Equal(corev1.ClaimBound))
+		Expect(claim.Spec.VolumeName).NotTo(BeEmpty())
+		volumeName = claim.Spec.VolumeName
+	})
+
+	It("should persist data across pods", func() {
+		if volumeName == "" {
+			Skip("no bound volume from the provisioning spec")
+		}
+		kube := testcontext.KubeClient()
+
+		log.Step("reading the data back through pod %s", readerPod)
+		_, err := kube.CoreV1().Pods(storageNamespace).Create(testcontext.Context(),
+			volumePod(readerPod, fmt.Sprintf("grep -q %s /data/sentinel", sentinel)),
+			metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		waitForPodSuccess(readerPod)
+	})
+
+	It("should expand the volume where the class supports it", func() {
+		if volumeName == "" {
+			Skip("no bound volume from the provisioning spec")
+		}
+		if !allowExpansion {
+			Skip(fmt.Sprintf("StorageClass %s on provider %q does not allow volume expansion", defaultClassName, provider))
+		}
+		kube := testcontext.KubeClient()
+
+		log.Step("expanding the claim from %s to %s", initialSize, expandedSize)
+		patch := []byte(fmt.Sprintf(`{"spec":{"resources":{"requests":{"storage":%q}}}}`, expandedSize))
+		_, err := kube.CoreV1().PersistentVolumeClaims(storageNamespace).Patch(
+			testcontext.Context(), claimName, types.MergePatchType, patch, metav1.PatchOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		expected := resource.MustParse(expandedSize)
+		Eventually(func() (string, error) {
+			claim, err := kube.CoreV1().PersistentVolumeClaims(storageNamespace).Get(
+				testcontext.Context(), claimName, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			capacity := claim.Status.Capacity[corev1.ResourceStorage]
+			return capacity.String(), nil
+		}, expandTimeout, storagePoll).Should(Equal(expected.String()),
+			"claim %s never reported the expanded capacity", claimName)
+	})
+
+	It("should release the backing volume on deletion", func() {
+		if volumeName == "" {
+			Skip("no bound volume from the provisioning spec")
+		}
+		kube := testcontext.KubeClient()
+
+		volume, err := kube.CoreV1().PersistentVolumes().Get(testcontext.Context(), volumeName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		reclaimPolicy := volume.Spec.PersistentVolumeReclaimPolicy
+
+		log.Step("deleting namespace %s and waiting for volume %s to be released", storageNamespace, volumeName)
+		err = kube.CoreV1().Namespaces().Delete(testcontext.Context(), storageNamespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// With the usual Delete reclaim policy the volume object disappears
+		// once the provider releases the backing disk; under Retain the
+		// best we can verify is the Released phase.
+		Eventually(func() (string, error) {
+			volume, err := kube.CoreV1().PersistentVolumes().Get(testcontext.Context(), volumeName, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return "released", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if reclaimPolicy == corev1.PersistentVolumeReclaimRetain && volume.Status.Phase == corev1.VolumeReleased {
+				return "released", nil
+			}
+			return fmt.Sprintf("volume still %s", volume.Status.Phase), nil
+		}, releaseTimeout, storagePoll).Should(Equal("released"),
+			"backing volume %s was never released after deleting the claim", volumeName)
+	})
+})
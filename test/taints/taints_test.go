@@ -0,0 +1,183 @@
+package taints
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/inventory"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+const (
+	// testTaintKey is namespaced under the e2e domain so the suite can never
+	// collide with taints users or controllers care about.
+	testTaintKey   = "e2e.containership.io/taints-test"
+	testTaintValue = "set-by-e2e"
+
+	repelledNamespace  = "e2e-taints-repelled"
+	repelledDeployment = "repelled"
+
+	propagationTimeout = 5 * time.Minute
+	propagationPoll    = 10 * time.Second
+	scheduleTimeout    = 3 * time.Minute
+	// repelWindow is how long the untolerated workload must stay pending
+	// before we accept that the taint is actually repelling it.
+	repelWindow = time.Minute
+)
+
+// poolNodes lists the Kubernetes nodes belonging to the worker pool under
+// test, selected by the node pool ID label the node sync controller stamps.
+func poolNodes() ([]corev1.Node, error) {
+	nodes, err := testcontext.KubeClient().CoreV1().Nodes().List(testcontext.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", inventory.NodePoolIDLabel, workerPoolID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// hasTestTaint returns true if the node carries the suite's taint.
+func hasTestTaint(node corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == testTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// allPoolNodesTainted returns true once every node in the pool carries the
+// test taint.
+func allPoolNodesTainted() (bool, error) {
+	nodes, err := poolNodes()
+	if err != nil {
+		return false, err
+	}
+	if len(nodes) == 0 {
+		return false, nil
+	}
+
+	for _, node := range nodes {
+		if !hasTestTaint(node) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// noPoolNodesTainted returns true once no node in the pool carries the
+// test taint.
+func noPoolNodesTainted() (bool, error) {
+	nodes, err := poolNodes()
+	if err != nil {
+		return false, err
+	}
+
+	for _, node := range nodes {
+		if hasTestTaint(node) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// readyReplicas returns the ready replica count of the repelled deployment.
+func readyReplicas() (int32, error) {
+	deployment, err := testcontext.KubeClient().AppsV1().Deployments(repelledNamespace).Get(
+		testcontext.Context(), repelledDeployment, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return deployment.Status.ReadyReplicas, nil
+}
+
+var _ = Describe("Node pool taint management", func() {
+	It("should apply configured taints to the pool's nodes", func() {
+		client := testcontext.CloudClient()
+		err := client.SetNodePoolTaints(testcontext.ClusterID(), workerPoolID, []cloud.Taint{
+			{Key: testTaintKey, Value: testTaintValue, Effect: "NoSchedule"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(allPoolNodesTainted, propagationTimeout, propagationPoll).Should(BeTrue(),
+			"nodes in pool %s never received taint %s", workerPoolID, testTaintKey)
+	})
+
+	It("should repel workloads without a matching toleration", func() {
+		kube := testcontext.KubeClient()
+
+		log.Step("deploying an untolerated workload pinned to the tainted pool")
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: repelledNamespace},
+		}
+		_, err := kube.CoreV1().Namespaces().Create(testcontext.Context(), namespace, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = kube.AppsV1().Deployments(repelledNamespace).Create(testcontext.Context(), repelledWorkload(), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		log.Step("verifying the workload stays unscheduled while the taint holds")
+		Consistently(readyReplicas, repelWindow, propagationPoll).Should(BeZero(),
+			"untolerated workload was scheduled onto tainted pool %s", workerPoolID)
+	})
+
+	It("should resume scheduling once the taints are removed", func() {
+		client := testcontext.CloudClient()
+		Expect(client.SetNodePoolTaints(testcontext.ClusterID(), workerPoolID, nil)).To(Succeed())
+
+		Eventually(noPoolNodesTainted, propagationTimeout, propagationPoll).Should(BeTrue(),
+			"taint %s was not removed from nodes in pool %s", testTaintKey, workerPoolID)
+
+		log.Step("verifying the previously repelled workload now schedules")
+		Eventually(readyReplicas, scheduleTimeout, propagationPoll).Should(BeNumerically(">", 0),
+			"workload did not schedule after taints were removed from pool %s", workerPoolID)
+
+		err := testcontext.KubeClient().CoreV1().Namespaces().Delete(testcontext.Context(), repelledNamespace, metav1.DeleteOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+// repelledWorkload returns a pause deployment node-selected onto the worker
+// pool under test with no toleration for the suite's taint.
+func repelledWorkload() *appsv1.Deployment {
+	labels := map[string]string{"app": repelledDeployment}
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   repelledDeployment,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{
+						inventory.NodePoolIDLabel: workerPoolID,
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "pause",
+							Image: "k8s.gcr.io/pause:3.2",
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/config"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/flakiness"
+	"github.com/containership/e2e-test/pkg/leakcheck"
+	"github.com/containership/e2e-test/pkg/log"
+	"github.com/containership/e2e-test/pkg/ownership"
+	"github.com/containership/e2e-test/pkg/progress"
+	"github.com/containership/e2e-test/pkg/quarantine"
+	"github.com/containership/e2e-test/pkg/reporting"
+	"github.com/containership/e2e-test/pkg/runmode"
+	"github.com/containership/e2e-test/pkg/templates"
+	"github.com/containership/e2e-test/pkg/testcontext"
+	"github.com/containership/e2e-test/pkg/webhook"
+)
+
+const templatePath = "../../templates/digitalocean.yaml"
+
+var (
+	receiver  *webhook.Receiver
+	webhookID string
+)
+
+// The webhooks suite registers a webhook pointing at a receiver the
+// harness controls, provisions and deletes a throwaway cluster, and
+// asserts the expected lifecycle deliveries arrive. It requires CI to
+// expose the receiver publicly via E2E_WEBHOOK_PUBLIC_URL.
+func TestWebhooks(t *testing.T) {
+	if token, _ := config.Token(); token == "" {
+		t.Skipf("%s is not set and no --config token was given; skipping e2e suite", constants.TokenEnvVar)
+	}
+	if webhook.PublicURL() == "" {
+		t.Skipf("%s is not set; the receiver is not publicly reachable", constants.WebhookPublicURLEnvVar)
+	}
+	if runmode.ReadOnly() {
+		t.Skip("webhooks suite mutates org state; skipped in read-only mode")
+	}
+
+	quarantineList, err := quarantine.LoadFromEnv()
+	if err != nil {
+		t.Fatalf("loading quarantine config: %v", err)
+	}
+
+	// Keep the harness itself honest on long runs: log memory stats
+	// periodically and fail if the suite leaks goroutines.
+	defer leakcheck.VerifyNone(t)
+	memStats := leakcheck.StartMemStatsLogger(GinkgoWriter, time.Minute)
+	defer memStats.Stop()
+
+	artifacts.SetSuite("webhooks")
+	log.SetConsole(GinkgoWriter)
+	log.SetSuite("webhooks")
+	defer func() {
+		if err := flakiness.Flush(); err != nil {
+			t.Logf("flushing spec history: %v", err)
+		}
+	}()
+	progress.SetSuite("webhooks")
+	progress.Start()
+	defer progress.Stop()
+
+	RegisterFailHandler(quarantine.FailHandler(quarantineList))
+	RunSpecsWithDefaultAndCustomReporters(t, "Webhooks Suite", reporting.Reporters("webhooks"))
+}
+
+// This suite keeps all state in one process (the receiver cannot be shared
+// across ginkgo nodes), so the setup runs unconditionally on the primary.
+var _ = BeforeSuite(func() {
+	client, err := cloud.New()
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetCloudClient(client)
+
+	receiver, err = webhook.StartReceiver()
+	Expect(err).NotTo(HaveOccurred())
+
+	registered, err := client.CreateWebhook(&cloud.CreateWebhookRequest{
+		URL:    webhook.PublicURL(),
+		Events: []string{cloud.EventClusterCreated, cloud.EventClusterDeleted},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	webhookID = registered.ID
+
+	rendered, err := templates.Render(templatePath, templates.DefaultVars(constants.DefaultKubernetesVersion))
+	Expect(err).NotTo(HaveOccurred())
+
+	template, err := client.CreateTemplate(rendered.Request)
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetTemplateID(template.ID)
+
+	cluster, err := client.CreateCluster(&cloud.CreateClusterRequest{
+		TemplateID: template.ID,
+		Name:       fmt.Sprintf("e2e-webhooks-%d", time.Now().Unix()),
+		Labels:     ownership.Labels(),
+	})
+	Expect(err).NotTo(HaveOccurred())
+	testcontext.SetClusterID(cluster.ID)
+
+	Expect(client.WaitForClusterRunning(cluster.ID, constants.ProvisionTimeout)).To(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	client := testcontext.CloudClient()
+	if client == nil {
+		return
+	}
+
+	// Best-effort teardown: the delete spec normally removes the cluster,
+	// but a failed run must not leak it into the shared org.
+	//nolint:errcheck
+	client.DeleteCluster(testcontext.ClusterID())
+	//nolint:errcheck
+	client.WaitForClusterDeleted(testcontext.ClusterID(), constants.DeleteTimeout)
+	//nolint:errcheck
+	client.DeleteTemplate(testcontext.TemplateID())
+
+	if webhookID != "" {
+		Expect(client.DeleteWebhook(webhookID)).To(Succeed())
+	}
+	if receiver != nil {
+		Expect(receiver.Stop()).To(Succeed())
+	}
+})
+
+// Scope artifact writes to the spec in flight so parallel nodes and
+// background collectors never clobber each other's diagnostics.
+var _ = BeforeEach(func() {
+	artifacts.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	log.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+	progress.SetSpec(CurrentGinkgoTestDescription().FullTestText)
+})
+
+var _ = AfterEach(func() {
+	desc := CurrentGinkgoTestDescription()
+	flakiness.Record(desc.FullTestText, desc.Failed)
+
+	artifacts.ClearSpec()
+	log.SetSpec("")
+	progress.SetSpec("")
+})
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/containership/e2e-test/pkg/artifacts"
+	"github.com/containership/e2e-test/pkg/cloud"
+	"github.com/containership/e2e-test/pkg/constants"
+	"github.com/containership/e2e-test/pkg/testcontext"
+)
+
+// deliveryTimeout is how long a lifecycle delivery may lag the operation
+// that triggered it.
+const deliveryTimeout = 5 * time.Minute
+
+var _ = Describe("Cluster lifecycle webhooks", func() {
+	It("should deliver a cluster.created webhook", func() {
+		delivery, err := receiver.WaitFor(cloud.EventClusterCreated, testcontext.ClusterID(), deliveryTimeout)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivery.Body).NotTo(BeEmpty())
+	})
+
+	It("should deliver a cluster.deleted webhook after deletion", func() {
+		client := testcontext.CloudClient()
+		Expect(client.DeleteCluster(testcontext.ClusterID())).To(Succeed())
+		Expect(client.WaitForClusterDeleted(testcontext.ClusterID(), constants.DeleteTimeout)).To(Succeed())
+
+		delivery, err := receiver.WaitFor(cloud.EventClusterDeleted, testcontext.ClusterID(), deliveryTimeout)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivery.Body).NotTo(BeEmpty())
+
+		//nolint:errcheck - artifact export is best-effort
+		artifacts.WriteJSON("webhook-deliveries.json", receiver.Deliveries())
+	})
+})